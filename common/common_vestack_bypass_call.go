@@ -21,7 +21,7 @@ const (
 
 type BypassSvc struct {
 	Session   *session.Session
-	endpoints map[string]string
+	overrides ServiceOverrides
 }
 
 type BypassSvcInfo struct {
@@ -35,10 +35,10 @@ type BypassSvcInfo struct {
 	Client      *client.Client
 }
 
-func NewBypassClient(session *session.Session, endpoints map[string]string) *BypassSvc {
+func NewBypassClient(session *session.Session, overrides ServiceOverrides) *BypassSvc {
 	return &BypassSvc{
 		Session:   session,
-		endpoints: endpoints,
+		overrides: overrides,
 	}
 }
 
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -270,11 +271,20 @@ func (c *SdkCall) InitReadCall(resourceData *schema.ResourceData, resource *sche
 }
 
 func CallProcess(calls []SdkCall, d *schema.ResourceData, client *SdkClient, service ResourceService) (err error) {
+	return CallProcessWithContext(client.StopContext(), calls, d, client, service)
+}
+
+// CallProcessWithContext stop before next call, lock or state refresh when ctx is done
+func CallProcessWithContext(ctx context.Context, calls []SdkCall, d *schema.ResourceData, client *SdkClient, service ResourceService) (err error) {
 	if len(calls) > 0 {
 		for _, fn := range calls {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
 			if fn.ExecuteCall != nil {
 				var (
-					resp *map[string]interface{}
+					resp   *map[string]interface{}
+					locked string
 				)
 				doExecute := true
 
@@ -305,17 +315,24 @@ func CallProcess(calls []SdkCall, d *schema.ResourceData, client *SdkClient, ser
 						}
 						fn.SdkParam = &jsonParam
 					}
-					if fn.LockId != nil {
+					if fn.LockId != nil && err == nil {
 						key := fn.LockId(d)
 						if key != "" {
-							TryLock(key)
+							err = client.TryLockContext(ctx, key)
+							if err == nil {
+								locked = key
+							}
 						}
 					}
-					if fn.AfterLocked != nil {
+					if fn.AfterLocked != nil && err == nil {
 						err = fn.AfterLocked(d, client, fn)
 					}
 					if err == nil {
-						resp, err = fn.ExecuteCall(d, client, fn)
+						err = client.RetryPolicy().ForCall(fn.Action, fn.SdkParam).Retry(ctx, fn.Action, func() error {
+							var callErr error
+							resp, callErr = fn.ExecuteCall(d, client, fn)
+							return callErr
+						})
 					}
 				}
 				if err != nil {
@@ -336,7 +353,7 @@ func CallProcess(calls []SdkCall, d *schema.ResourceData, client *SdkClient, ser
 						stateConf = service.RefreshResourceState(d, fn.Refresh.Target, fn.Refresh.Timeout, d.Id())
 					}
 					if stateConf != nil {
-						_, err = stateConf.WaitForState()
+						_, err = WaitForStateContext(ctx, stateConf)
 					}
 				}
 
@@ -356,7 +373,10 @@ func CallProcess(calls []SdkCall, d *schema.ResourceData, client *SdkClient, ser
 					for k, v := range fn.ExtraRefresh {
 						stateConf := k.RefreshResourceState(d, v.Target, v.Timeout, v.ResourceId)
 						if stateConf != nil {
-							_, err = stateConf.WaitForState()
+							_, err = WaitForStateContext(ctx, stateConf)
+						}
+						if err != nil {
+							break
 						}
 					}
 				}
@@ -365,11 +385,8 @@ func CallProcess(calls []SdkCall, d *schema.ResourceData, client *SdkClient, ser
 					err = fn.AfterRefresh(d, client, fn)
 				}
 
-				if doExecute && fn.LockId != nil {
-					key := fn.LockId(d)
-					if key != "" {
-						ReleaseLock(key)
-					}
+				if locked != "" {
+					client.ReleaseLock(locked)
 				}
 				if err != nil {
 					return err
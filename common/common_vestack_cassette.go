@@ -0,0 +1,314 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+
+	cassetteScrubbed = "SCRUBBED"
+)
+
+// cassetteIgnoreParams params which change every run and can not be used to match interaction
+var cassetteIgnoreParams = map[string]bool{
+	"ClientToken": true,
+}
+
+// cassetteScrubHeaders credentials and request ids in header
+var cassetteScrubHeaders = []string{
+	"Authorization",
+	"X-Security-Token",
+	"X-Date",
+	"X-Request-Id",
+	"X-Tos-Request-Id",
+	"X-Tos-Id-2",
+	"X-Tls-Requestid",
+}
+
+// cassetteScrubBody credentials and request ids in json body
+var cassetteScrubBody = regexp.MustCompile(`"(RequestId|SecretAccessKey|AccessKeySecret|SessionToken|Password)"\s*:\s*"[^"]*"`)
+
+type Cassette struct {
+	Interactions []*CassetteInteraction
+
+	filePath string
+	mode     string
+	lock     sync.Mutex
+	used     map[int]bool
+}
+
+type CassetteInteraction struct {
+	Request  CassetteRequest
+	Response CassetteResponse
+}
+
+type CassetteRequest struct {
+	Method string
+	Host   string
+	Path   string
+	Action string
+	// Params canonical params exclude cassetteIgnoreParams, used to match interaction
+	Params string
+}
+
+type CassetteResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Body       string
+}
+
+var (
+	cassettes     = make(map[string]*Cassette)
+	cassettesLock sync.Mutex
+)
+
+// OpenCassette every provider configure in one process share the same cassette of a file
+func OpenCassette(mode string, filePath string) (*Cassette, error) {
+	if mode != CassetteModeRecord && mode != CassetteModeReplay {
+		return nil, fmt.Errorf("cassette mode must be %s or %s, got %s", CassetteModeRecord, CassetteModeReplay, mode)
+	}
+	if filePath == "" {
+		return nil, fmt.Errorf("cassette file must be set in %s mode", mode)
+	}
+	absPath, err := absolutePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cassettesLock.Lock()
+	defer cassettesLock.Unlock()
+	if c, ok := cassettes[absPath]; ok {
+		if c.mode != mode {
+			return nil, fmt.Errorf("cassette %s is already opened in %s mode", absPath, c.mode)
+		}
+		return c, nil
+	}
+
+	c := &Cassette{
+		filePath: absPath,
+		mode:     mode,
+		used:     make(map[int]bool),
+	}
+	if mode == CassetteModeReplay {
+		var bs []byte
+		bs, err = ioutil.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette %s error: %w", absPath, err)
+		}
+		if err = json.Unmarshal(bs, &c.Interactions); err != nil {
+			return nil, fmt.Errorf("unmarshal cassette %s error: %w", absPath, err)
+		}
+	}
+	cassettes[absPath] = c
+	return c, nil
+}
+
+// HttpClient wrap the transport of base, record or replay every request
+func (c *Cassette) HttpClient(base *http.Client) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	next := base.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: &cassetteTransport{
+			cassette: c,
+			next:     next,
+		},
+		CheckRedirect: base.CheckRedirect,
+		Jar:           base.Jar,
+		Timeout:       base.Timeout,
+	}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	request := newCassetteRequest(req, body)
+
+	if t.cassette.mode == CassetteModeReplay {
+		interaction, err := t.cassette.match(request)
+		if err != nil {
+			return nil, err
+		}
+		return interaction.Response.toHttpResponse(req), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := make(map[string]string)
+	for k := range resp.Header {
+		header[k] = resp.Header.Get(k)
+	}
+	err = t.cassette.record(&CassetteInteraction{
+		Request: request,
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubCassetteHeader(header),
+			Body:       scrubCassetteBody(string(respBody)),
+		},
+	})
+	return resp, err
+}
+
+func (c *Cassette) match(request CassetteRequest) (*CassetteInteraction, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, interaction := range c.Interactions {
+		if c.used[i] {
+			continue
+		}
+		if interaction.Request == request {
+			c.used[i] = true
+			return interaction, nil
+		}
+	}
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s%s %s %s", c.filePath,
+		request.Method, request.Host, request.Path, request.Action, request.Params)
+}
+
+func (c *Cassette) record(interaction *CassetteInteraction) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+	bs, err := json.MarshalIndent(c.Interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.filePath, bs, 0644)
+}
+
+func newCassetteRequest(req *http.Request, body []byte) CassetteRequest {
+	params := url.Values{}
+	for k, v := range req.URL.Query() {
+		params[k] = v
+	}
+	contentType := strings.ToLower(req.Header.Get("Content-Type"))
+	if len(body) > 0 && strings.Contains(contentType, "x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for k, v := range form {
+				params[k] = v
+			}
+		}
+	}
+	var (
+		keys   []string
+		parts  []string
+		action string
+	)
+	for k := range params {
+		if cassetteIgnoreParams[k] {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := strings.Join(params[k], ",")
+		if cassetteSensitiveParam(k) {
+			v = cassetteScrubbed
+		}
+		parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+	}
+	if len(body) > 0 && strings.Contains(contentType, "application/json") {
+		parts = append(parts, "body="+canonicalCassetteJson(body))
+	}
+	action = params.Get("Action")
+	return CassetteRequest{
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+		Action: action,
+		Params: strings.Join(parts, "&"),
+	}
+}
+
+// canonicalCassetteJson sort keys and drop cassetteIgnoreParams of json body
+func canonicalCassetteJson(body []byte) string {
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return string(body)
+	}
+	for k := range cassetteIgnoreParams {
+		delete(m, k)
+	}
+	for k := range m {
+		if cassetteSensitiveParam(k) {
+			m[k] = cassetteScrubbed
+		}
+	}
+	bs, _ := json.Marshal(m)
+	return string(bs)
+}
+
+// cassetteSensitiveParam request params like Password and SecretKey must not be written to cassette
+func cassetteSensitiveParam(key string) bool {
+	k := strings.ToLower(key)
+	return strings.Contains(k, "password") || strings.Contains(k, "secret") || strings.Contains(k, "sessiontoken")
+}
+
+func scrubCassetteHeader(header map[string]string) map[string]string {
+	for _, k := range cassetteScrubHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(k)]; ok {
+			header[http.CanonicalHeaderKey(k)] = cassetteScrubbed
+		}
+	}
+	return header
+}
+
+func scrubCassetteBody(body string) string {
+	return cassetteScrubBody.ReplaceAllString(body, `"$1":"`+cassetteScrubbed+`"`)
+}
+
+func (r CassetteResponse) toHttpResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range r.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cassette_RecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "vpc.json")
+
+	server := NewMockServer()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-1", "Status": "Available"},
+	}}})
+	server.On("CreateVpc", MockResponse{Result: map[string]interface{}{"VpcId": "vpc-2"}})
+
+	config := server.Config()
+	config.CassetteMode = CassetteModeRecord
+	config.CassetteFile = file
+	client, err := config.Client()
+	assert.Nil(t, err)
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &map[string]interface{}{
+		"VpcIds.1": "vpc-1",
+	})
+	assert.Nil(t, err)
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("CreateVpc"), &map[string]interface{}{
+		"VpcName":     "foo",
+		"ClientToken": "token-1",
+		"Password":    "p@ssw0rd",
+	})
+	assert.Nil(t, err)
+	server.Close()
+
+	bs, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	content := string(bs)
+	assert.False(t, strings.Contains(content, "mock-request-"))
+	assert.False(t, strings.Contains(content, "mock-access-key"))
+	assert.False(t, strings.Contains(content, "p@ssw0rd"))
+	assert.False(t, strings.Contains(content, "token-1"))
+
+	// server is closed, every request must be served from cassette
+	config.CassetteMode = CassetteModeReplay
+	config.CassetteFile = filepath.Join(dir, ".", "vpc.json")
+	delete(cassettes, file)
+	client, err = config.Client()
+	assert.Nil(t, err)
+	out, err := client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &map[string]interface{}{
+		"VpcIds.1": "vpc-1",
+	})
+	assert.Nil(t, err)
+	status, _ := ObtainSdkValue("Result.Vpcs.0.Status", *out)
+	assert.Equal(t, "Available", status)
+
+	out, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("CreateVpc"), &map[string]interface{}{
+		"VpcName":     "foo",
+		"ClientToken": "token-2",
+		"Password":    "another",
+	})
+	assert.Nil(t, err)
+	id, _ := ObtainSdkValue("Result.VpcId", *out)
+	assert.Equal(t, "vpc-2", id)
+
+	// every interaction can be replayed only once
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &map[string]interface{}{
+		"VpcIds.1": "vpc-1",
+	})
+	assert.NotNil(t, err)
+}
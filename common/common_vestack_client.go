@@ -1,6 +1,8 @@
 package common
 
 import (
+	"context"
+
 	"github.com/volcengine/volcengine-go-sdk/service/autoscaling"
 	"github.com/volcengine/volcengine-go-sdk/service/clb"
 	"github.com/volcengine/volcengine-go-sdk/service/ecs"
@@ -10,6 +12,7 @@ import (
 	"github.com/volcengine/volcengine-go-sdk/service/storageebs"
 	"github.com/volcengine/volcengine-go-sdk/service/vpc"
 	"github.com/volcengine/volcengine-go-sdk/service/vpn"
+	"golang.org/x/sync/semaphore"
 )
 
 type SdkClient struct {
@@ -25,4 +28,26 @@ type SdkClient struct {
 	RdsClientV2       *rdsmysqlv2.RDSMYSQLV2
 	UniversalClient   *Universal
 	BypassSvcClient   *BypassSvc
+
+	stopContext   context.Context
+	rateLimits    rateLimits
+	syncSemaphore *semaphore.Weighted
+	retryPolicy   *RetryPolicy
+	rates         *scopedRates
+	readCache     *readCache
+
+	// account the identity of the credentials, the locks are shared by the clients of the same account and region
+	account string
+	locks   *lockSet
+
+	// shared by the clients of all regions of the provider instance
+	config  *Config
+	regions *regionClients
+}
+
+func (c *SdkClient) rate(service, operation string) *Rate {
+	if c == nil || c.rateLimits == nil {
+		return nil
+	}
+	return c.rateLimits.get(service, operation)
 }
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// clientCacheKey the custom http client is compared by identity, every other setting of the session is part of
+// the fingerprint. The stop context is bound to the copies of the cached clients by every provider instance
+type clientCacheKey struct {
+	fingerprint string
+	httpClient  *http.Client
+}
+
+// maxCachedClients the least recently used clients are evicted beyond it
+const maxCachedClients = 64
+
+var (
+	clientCacheLock sync.Mutex
+	clientCache     = make(map[clientCacheKey]*SdkClient)
+	// clientCacheKeys the keys of clientCache, the least recently used first
+	clientCacheKeys []clientCacheKey
+)
+
+// cachedClients the sdk clients of the same credentials, region and endpoint are built once,
+// clients of the cassette are never cached
+func (c *Config) cachedClients() (*SdkClient, error) {
+	if c.CassetteMode != "" {
+		return c.newClients()
+	}
+	key := clientCacheKey{
+		fingerprint: c.fingerprint(),
+		httpClient:  c.HttpClient,
+	}
+	clientCacheLock.Lock()
+	defer clientCacheLock.Unlock()
+	if client, ok := clientCache[key]; ok {
+		touchCachedClients(key)
+		return client, nil
+	}
+	client, err := c.newClients()
+	if err != nil {
+		return nil, err
+	}
+	if len(clientCacheKeys) >= maxCachedClients {
+		delete(clientCache, clientCacheKeys[0])
+		clientCacheKeys = clientCacheKeys[1:]
+	}
+	clientCache[key] = client
+	clientCacheKeys = append(clientCacheKeys, key)
+	return client, nil
+}
+
+// touchCachedClients move key to the end of clientCacheKeys, clientCacheLock must be held
+func touchCachedClients(key clientCacheKey) {
+	for i, k := range clientCacheKeys {
+		if k == key {
+			clientCacheKeys = append(append(clientCacheKeys[:i:i], clientCacheKeys[i+1:]...), key)
+			return
+		}
+	}
+}
+
+// fingerprint hash of credentials, region, endpoint and transport, secrets never leave the hash
+func (c *Config) fingerprint() string {
+	overrides := make(map[string]interface{})
+	for k, v := range c.ServiceOverrides {
+		maxRetries := -1
+		if v.MaxRetries != nil {
+			maxRetries = *v.MaxRetries
+		}
+		overrides[k] = []interface{}{v.Endpoint, v.Scheme, v.SigningRegion, v.Timeout, maxRetries}
+	}
+	bs, _ := json.Marshal([]interface{}{
+		c.AccessKey, c.SecretKey, c.SessionToken, c.Profile, c.SharedCredentialsFile, c.AssumeRole,
+		c.Region, c.Endpoint, c.DisableSSL, c.CustomerHeaders, c.CustomerEndpoints, overrides,
+		c.ProxyUrl, c.Transport,
+	})
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
+
+// regionClients the clients of every region used by one provider instance
+type regionClients struct {
+	lock    sync.Mutex
+	clients map[string]*SdkClient
+}
+
+func newRegionClients() *regionClients {
+	return &regionClients{
+		clients: make(map[string]*SdkClient),
+	}
+}
+
+// ForRegion the client of region which shares the credentials of this provider instance, the locks are the ones
+// of the account and region, the semaphore and rates are owned by the region. Empty region is the provider region
+func (c *SdkClient) ForRegion(region string) (*SdkClient, error) {
+	if region == "" || region == c.Region || c.regions == nil {
+		return c, nil
+	}
+	c.regions.lock.Lock()
+	defer c.regions.lock.Unlock()
+	if client, ok := c.regions.clients[region]; ok {
+		return client, nil
+	}
+
+	config := *c.config
+	config.Region = region
+	clients, err := config.cachedClients()
+	if err != nil {
+		return nil, err
+	}
+	client := *clients
+	client.config = &config
+	client.locks = accountLockSet(client.account, region)
+	client.regions = c.regions
+	if err = config.initClientState(&client); err != nil {
+		return nil, err
+	}
+	c.regions.clients[region] = &client
+	return &client, nil
+}
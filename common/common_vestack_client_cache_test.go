@@ -0,0 +1,177 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/vpc"
+)
+
+func Test_ClientCache(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+
+	config := server.Config()
+	first, err := config.Client()
+	assert.Nil(t, err)
+	second, err := config.Client()
+	assert.Nil(t, err)
+	// sdk clients and locks are shared, the other state is owned by every provider instance
+	assert.True(t, first.VpcClient == second.VpcClient)
+	assert.True(t, first.UniversalClient == second.UniversalClient)
+	assert.True(t, first.locks == second.locks)
+	assert.False(t, first.syncSemaphore == second.syncSemaphore)
+
+	// the provider instances of the same account and region serialize the operations of a resource
+	assert.Nil(t, first.TryLockContext(context.Background(), "vpc-1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, second.TryLockContext(ctx, "vpc-1"))
+	first.ReleaseLock("vpc-1")
+	assert.Nil(t, second.TryLockContext(context.Background(), "vpc-1"))
+	second.ReleaseLock("vpc-1")
+
+	// the locks of another account
+	otherAccount := server.Config()
+	otherAccount.AccessKey = "mock-other-key"
+	third, err := otherAccount.Client()
+	assert.Nil(t, err)
+	assert.False(t, first.locks == third.locks)
+
+	r := NewRate(1, 1, 1)
+	assert.True(t, first.scopedRate(r) == first.scopedRate(r))
+	assert.False(t, first.scopedRate(r) == second.scopedRate(r))
+	assert.False(t, first.scopedRate(r) == r)
+
+	config.Region = "cn-mock-2"
+	other, err := config.Client()
+	assert.Nil(t, err)
+	assert.False(t, first.VpcClient == other.VpcClient)
+}
+
+func Test_ClientCache_StopContext(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	stopped, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := server.Config()
+	config.StopContext = stopped
+	first, err := config.Client()
+	assert.Nil(t, err)
+	config = server.Config()
+	config.StopContext = context.Background()
+	second, err := config.Client()
+	assert.Nil(t, err)
+
+	// the provider instances share the cached clients, the stop context is bound to every provider instance
+	cached, err := config.cachedClients()
+	assert.Nil(t, err)
+	config.StopContext = stopped
+	again, err := config.cachedClients()
+	assert.Nil(t, err)
+	assert.True(t, cached == again)
+	assert.True(t, first.config.fingerprint() == second.config.fingerprint())
+
+	_, err = first.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+	assert.NotNil(t, err)
+	_, err = first.VpcClient.DescribeVpcs(&vpc.DescribeVpcsInput{})
+	assert.NotNil(t, err)
+	_, err = second.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(server.Requests("DescribeVpcs")))
+}
+
+func Test_ClientCache_Evict(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+
+	config := server.Config()
+	first, err := config.cachedClients()
+	assert.Nil(t, err)
+	for i := 0; i < maxCachedClients; i++ {
+		other := server.Config()
+		other.AccessKey = fmt.Sprintf("mock-evict-key-%d", i)
+		_, err = other.cachedClients()
+		assert.Nil(t, err)
+	}
+	assert.True(t, len(clientCache) <= maxCachedClients)
+	assert.Equal(t, len(clientCache), len(clientCacheKeys))
+	again, err := config.cachedClients()
+	assert.Nil(t, err)
+	assert.False(t, first == again)
+}
+
+func Test_ClientForRegion(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	client, err := server.Config().Client()
+	assert.Nil(t, err)
+	same, err := client.ForRegion("")
+	assert.Nil(t, err)
+	assert.True(t, client == same)
+	same, err = client.ForRegion("cn-mock")
+	assert.Nil(t, err)
+	assert.True(t, client == same)
+
+	regional, err := client.ForRegion("cn-mock-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "cn-mock-2", regional.Region)
+	assert.False(t, client.locks == regional.locks)
+	// the locks of the region are shared by the provider instances of the account
+	provider, err := server.Config().Client()
+	assert.Nil(t, err)
+	providerRegional, err := provider.ForRegion("cn-mock-2")
+	assert.Nil(t, err)
+	assert.True(t, regional.locks == providerRegional.locks)
+	assert.False(t, client.syncSemaphore == regional.syncSemaphore)
+	again, err := client.ForRegion("cn-mock-2")
+	assert.Nil(t, err)
+	assert.True(t, regional == again)
+	back, err := regional.ForRegion("cn-mock")
+	assert.Nil(t, err)
+	assert.True(t, client == back)
+
+	_, err = regional.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+	assert.Nil(t, err)
+	assert.Contains(t, server.Requests("DescribeVpcs")[0].Header.Get("Authorization"), "/cn-mock-2/vpc/")
+}
+
+func Test_ResourceRegionOverride(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	client, err := server.Config().Client()
+	assert.Nil(t, err)
+
+	var regions []string
+	record := func(d *schema.ResourceData, meta interface{}) error {
+		regions = append(regions, meta.(*SdkClient).Region)
+		d.SetId("vpc-1")
+		return nil
+	}
+	r := ResourceRegionOverride(&schema.Resource{
+		Create: record,
+		Read:   record,
+		Delete: record,
+		Schema: map[string]*schema.Schema{
+			"vpc_name": {Type: schema.TypeString, Optional: true, ForceNew: true},
+		},
+	})
+	assert.True(t, r.Schema[RegionField].ForceNew)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	assert.Nil(t, r.Create(d, client))
+	assert.Equal(t, "cn-mock", d.Get(RegionField))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{RegionField: "cn-mock-2"})
+	assert.Nil(t, r.Create(d, client))
+	assert.Nil(t, r.Delete(d, client))
+	assert.Equal(t, "cn-mock-2", d.Get(RegionField))
+	assert.Equal(t, []string{"cn-mock", "cn-mock-2", "cn-mock-2"}, regions)
+}
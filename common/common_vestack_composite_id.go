@@ -0,0 +1,87 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const CompositeIdSeparator = ":"
+
+// CompositeId the id of association resources, the values of the fields joined by `:`, e.g. `vtb-1:subnet-1`.
+// Only the value of the last field may contain `:`, e.g. the object name of tos object
+type CompositeId struct {
+	fields []string
+}
+
+// NewCompositeId the fields are the schema fields of the resource in order of the id
+func NewCompositeId(fields ...string) *CompositeId {
+	return &CompositeId{
+		fields: fields,
+	}
+}
+
+// Format e.g. `route_table_id:subnet_id`
+func (c *CompositeId) Format() string {
+	return strings.Join(c.fields, CompositeIdSeparator)
+}
+
+// Encode join the values in order of the fields
+func (c *CompositeId) Encode(values ...string) (string, error) {
+	if len(values) != len(c.fields) {
+		return "", fmt.Errorf("id of format %s needs %d values, got %d", c.Format(), len(c.fields), len(values))
+	}
+	for i, v := range values {
+		if v == "" {
+			return "", fmt.Errorf("%s of id %s is empty", c.fields[i], c.Format())
+		}
+		if i < len(values)-1 && strings.Contains(v, CompositeIdSeparator) {
+			return "", fmt.Errorf("%s %q of id %s must not contain %q", c.fields[i], v, c.Format(), CompositeIdSeparator)
+		}
+	}
+	return strings.Join(values, CompositeIdSeparator), nil
+}
+
+// Decode split id into the values in order of the fields
+func (c *CompositeId) Decode(id string) ([]string, error) {
+	values := strings.SplitN(id, CompositeIdSeparator, len(c.fields))
+	if len(values) != len(c.fields) {
+		return nil, fmt.Errorf("invalid id %q, the id must be of the form %s", id, c.Format())
+	}
+	for i, v := range values {
+		if v == "" {
+			return nil, fmt.Errorf("invalid id %q, %s is empty, the id must be of the form %s", id, c.fields[i], c.Format())
+		}
+	}
+	return values, nil
+}
+
+// ResourceId encode the values of the fields of d
+func (c *CompositeId) ResourceId(d *schema.ResourceData) (string, error) {
+	var values []string
+	for _, field := range c.fields {
+		values = append(values, fmt.Sprint(d.Get(field)))
+	}
+	return c.Encode(values...)
+}
+
+// Importer import by the composite id, every field of the id is set into the resource
+func (c *CompositeId) Importer() *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		State: c.ImportState,
+	}
+}
+
+func (c *CompositeId) ImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	values, err := c.Decode(d.Id())
+	if err != nil {
+		return []*schema.ResourceData{d}, err
+	}
+	for i, field := range c.fields {
+		if err = d.Set(field, values[i]); err != nil {
+			return []*schema.ResourceData{d}, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CompositeId(t *testing.T) {
+	id := NewCompositeId("bucket_name", "object_name")
+	assert.Equal(t, "bucket_name:object_name", id.Format())
+
+	v, err := id.Encode("bucket", "dir/a:b")
+	assert.Nil(t, err)
+	assert.Equal(t, "bucket:dir/a:b", v)
+	values, err := id.Decode(v)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bucket", "dir/a:b"}, values)
+
+	_, err = id.Encode("bucket")
+	assert.NotNil(t, err)
+	_, err = id.Encode("a:b", "object")
+	assert.NotNil(t, err)
+	_, err = id.Decode("bucket")
+	assert.EqualError(t, err, `invalid id "bucket", the id must be of the form bucket_name:object_name`)
+	_, err = id.Decode(":object")
+	assert.EqualError(t, err, `invalid id ":object", bucket_name is empty, the id must be of the form bucket_name:object_name`)
+}
+
+func Test_CompositeId_Import(t *testing.T) {
+	id := NewCompositeId("route_table_id", "subnet_id")
+	r := &schema.Resource{
+		Importer: id.Importer(),
+		Schema: map[string]*schema.Schema{
+			"route_table_id": {Type: schema.TypeString, Required: true, ForceNew: true},
+			"subnet_id":      {Type: schema.TypeString, Required: true, ForceNew: true},
+		},
+	}
+	d := r.Data(nil)
+	d.SetId("vtb-1:subnet-1")
+	result, err := r.Importer.State(d, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "vtb-1", result[0].Get("route_table_id"))
+	assert.Equal(t, "subnet-1", result[0].Get("subnet_id"))
+	v, err := id.ResourceId(result[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "vtb-1:subnet-1", v)
+
+	d.SetId("vtb-1")
+	_, err = r.Importer.State(d, nil)
+	assert.NotNil(t, err)
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/volcengine/terraform-provider-vestack/logger"
 	"github.com/volcengine/volcengine-go-sdk/volcengine"
 	"net/http"
-	"net/url"
 
 	"github.com/volcengine/volcengine-go-sdk/service/autoscaling"
 	"github.com/volcengine/volcengine-go-sdk/service/clb"
@@ -17,9 +16,9 @@ import (
 	"github.com/volcengine/volcengine-go-sdk/service/storageebs"
 	"github.com/volcengine/volcengine-go-sdk/service/vpc"
 	"github.com/volcengine/volcengine-go-sdk/service/vpn"
-	"github.com/volcengine/volcengine-go-sdk/volcengine/credentials"
 	"github.com/volcengine/volcengine-go-sdk/volcengine/session"
 	"github.com/volcengine/volcengine-go-sdk/volcengine/volcengineutil"
+	"golang.org/x/sync/semaphore"
 )
 
 type Config struct {
@@ -32,17 +31,84 @@ type Config struct {
 	CustomerHeaders   map[string]string
 	CustomerEndpoints map[string]string
 	ProxyUrl          string
+	// HttpClient is built from ProxyUrl and Transport when nil
+	HttpClient   *http.Client
+	Transport    HttpTransport
+	CassetteMode string
+	CassetteFile string
+	// StopContext cancelled when terraform stop the provider
+	StopContext context.Context
+	// RateLimits qps and concurrency of every service and operation
+	RateLimits []RateLimit
+	// MaxSyncConcurrency goroutines to read extra info of resource, default 10
+	MaxSyncConcurrency int
+	// RetryPolicy retry of every sdk call, DefaultRetryPolicy when nil
+	RetryPolicy *RetryPolicy
+	// SharedCredentialsFile and Profile are used when AccessKey and env are not set
+	SharedCredentialsFile string
+	Profile               string
+	AssumeRole            *AssumeRole
+	// ServiceOverrides endpoint, timeout and retries of every service, CustomerEndpoints is merged into it
+	ServiceOverrides map[string]ServiceOverride
+	// DefaultTags merged into the tags of every resource with tags
+	DefaultTags map[string]string
+	IgnoreTags  IgnoreTags
+	// ReadCache cache the results of resource and data source reads in one run
+	ReadCache bool
 }
 
+// Client the sdk clients are cached by credentials, region and endpoint, the locks are shared by the clients
+// of the same account and region, the stop context, semaphores and rates are owned by the returned client of this
+// provider instance
 func (c *Config) Client() (*SdkClient, error) {
+	clients, err := c.cachedClients()
+	if err != nil {
+		return nil, err
+	}
+	config := *c
+	client := *clients
+	client.config = &config
+	client.locks = accountLockSet(client.account, c.Region)
+	client.regions = newRegionClients()
+	if err = config.initClientState(&client); err != nil {
+		return nil, err
+	}
+	client.regions.clients[c.Region] = &client
+	return &client, nil
+}
+
+// initClientState the state of one provider instance and region
+func (c *Config) initClientState(client *SdkClient) (err error) {
+	client.rateLimits, err = newRateLimits(c.RateLimits)
+	if err != nil {
+		return err
+	}
+	client.retryPolicy = c.RetryPolicy
+	concurrency := c.MaxSyncConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultSyncConcurrency
+	}
+	client.syncSemaphore = semaphore.NewWeighted(int64(concurrency))
+	client.rates = newScopedRates()
+	client.bindStopContext(c.StopContext)
+	if c.ReadCache {
+		client.readCache = newReadCache()
+	}
+	return nil
+}
+
+// newClients the session and sdk clients without state
+func (c *Config) newClients() (*SdkClient, error) {
 	var client SdkClient
 	version := fmt.Sprintf("%s/%s", TerraformProviderName, TerraformProviderVersion)
 
 	config := volcengine.NewConfig().
 		WithRegion(c.Region).
 		WithExtraUserAgent(volcengine.String(version)).
-		WithCredentials(credentials.NewStaticCredentials(c.AccessKey, c.SecretKey, c.SessionToken)).
+		WithCredentials(c.baseCredentials()).
 		WithDisableSSL(c.DisableSSL).
+		// the requests are retried by RetryPolicy, the sdk retries are only enabled by ServiceOverride.MaxRetries
+		WithMaxRetries(0).
 		WithExtendHttpRequest(func(ctx context.Context, request *http.Request) {
 			if len(c.CustomerHeaders) > 0 {
 				for k, v := range c.CustomerHeaders {
@@ -51,45 +117,80 @@ func (c *Config) Client() (*SdkClient, error) {
 			}
 		}).
 		WithEndpoint(volcengineutil.NewEndpoint().WithCustomerEndpoint(c.Endpoint).GetEndpoint())
-	logger.Info("AccessKey: %+v", c.AccessKey)
-	logger.Info("SecretKey: %+v", c.SecretKey)
+	// never log secret key and session token
+	logger.Info("AccessKey: %s", logger.Mask(c.AccessKey))
+	logger.Info("Profile: %+v", c.Profile)
 	logger.Info("Region: %+v", c.Region)
 	logger.Info("CustomerHeaders: %+v", c.CustomerHeaders)
-	logger.Info("SessionToken: %+v", c.SessionToken)
 	logger.Info("Endpoint: %+v", c.Endpoint)
 
-	if c.ProxyUrl != "" {
-		u, _ := url.Parse(c.ProxyUrl)
-		t := &http.Transport{
-			Proxy: http.ProxyURL(u),
+	httpClient := c.HttpClient
+	if httpClient == nil {
+		var err error
+		httpClient, err = NewHttpClient(c.ProxyUrl, c.Transport)
+		if err != nil {
+			return nil, err
 		}
-		httpClient := http.DefaultClient
-		httpClient.Transport = t
 	}
+	if c.CassetteMode != "" {
+		cassette, err := OpenCassette(c.CassetteMode, c.CassetteFile)
+		if err != nil {
+			return nil, err
+		}
+		httpClient = cassette.HttpClient(httpClient)
+	}
+	config.WithHTTPClient(httpClient)
 
-	sess, err := session.NewSession(config)
+	overrides, err := NewServiceOverrides(c.CustomerEndpoints, c.ServiceOverrides)
 	if err != nil {
-		return nil, fmt.Errorf("session init error %w", err)
+		return nil, err
+	}
+	sess, err := c.newSession(config)
+	if err != nil {
+		return nil, err
+	}
+	if c.AssumeRole != nil {
+		// sts is called with the base credentials, other services with the assumed one
+		roleConfig := config.Copy().WithCredentials(newAssumeRoleCredentials(NewUniversalClient(sess, overrides), *c.AssumeRole))
+		sess, err = c.newSession(roleConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	value, err := sess.Config.Credentials.Get()
+	if err != nil {
+		return nil, fmt.Errorf("no valid credentials: %w", err)
+	}
+	// the access key of the assumed role is temporary, the role identifies the account
+	client.account = "ak:" + value.AccessKeyID
+	if c.AssumeRole != nil {
+		client.account = "role:" + c.AssumeRole.RoleTrn
 	}
 
 	client.Region = c.Region
-	client.VpcClient = vpc.New(sess)
-	client.ClbClient = clb.New(sess)
-	client.EcsClient = ecs.New(sess)
-	client.EbsClient = storageebs.New(sess)
-	client.VpnClient = vpn.New(sess)
-	client.NatClient = natgateway.New(sess)
-	client.AutoScalingClient = autoscaling.New(sess)
-	client.RdsClient = rdsmysql.New(sess)
-	client.RdsClientV2 = rdsmysqlv2.New(sess)
-	client.UniversalClient = NewUniversalClient(sess, c.CustomerEndpoints)
-	client.BypassSvcClient = NewBypassClient(sess, c.CustomerEndpoints)
-
-	//InitLocks()
-	//InitSyncLimit()
+	client.VpcClient = vpc.New(sess, overrides.configs(sess, vpc.ServiceName)...)
+	client.ClbClient = clb.New(sess, overrides.configs(sess, clb.ServiceName)...)
+	client.EcsClient = ecs.New(sess, overrides.configs(sess, ecs.ServiceName)...)
+	client.EbsClient = storageebs.New(sess, overrides.configs(sess, storageebs.ServiceName)...)
+	client.VpnClient = vpn.New(sess, overrides.configs(sess, vpn.ServiceName)...)
+	client.NatClient = natgateway.New(sess, overrides.configs(sess, natgateway.ServiceName)...)
+	client.AutoScalingClient = autoscaling.New(sess, overrides.configs(sess, autoscaling.ServiceName)...)
+	client.RdsClient = rdsmysql.New(sess, overrides.configs(sess, rdsmysql.ServiceName)...)
+	client.RdsClientV2 = rdsmysqlv2.New(sess, overrides.configs(sess, rdsmysqlv2.ServiceName)...)
+	client.UniversalClient = NewUniversalClient(sess, overrides)
+	client.BypassSvcClient = NewBypassClient(sess, overrides)
 	return &client, nil
 }
 
+func (c *Config) newSession(config *volcengine.Config) (*session.Session, error) {
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("session init error %w", err)
+	}
+	sess.Handlers.Complete.PushBackNamed(logRequestHandler)
+	return sess, nil
+}
+
 func init() {
 	InitLocks()
 	InitSyncLimit()
@@ -0,0 +1,137 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/volcengine/volcengine-go-sdk/service/autoscaling"
+	"github.com/volcengine/volcengine-go-sdk/service/clb"
+	"github.com/volcengine/volcengine-go-sdk/service/ecs"
+	"github.com/volcengine/volcengine-go-sdk/service/natgateway"
+	"github.com/volcengine/volcengine-go-sdk/service/rdsmysql"
+	"github.com/volcengine/volcengine-go-sdk/service/rdsmysqlv2"
+	"github.com/volcengine/volcengine-go-sdk/service/storageebs"
+	"github.com/volcengine/volcengine-go-sdk/service/vpc"
+	"github.com/volcengine/volcengine-go-sdk/service/vpn"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/client"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/request"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/session"
+)
+
+// StopContext is cancelled when terraform stop the provider (Ctrl-C or cancel),
+// every sdk request, rate limiter, lock and state refresh of this client honor it
+func (c *SdkClient) StopContext() context.Context {
+	if c == nil || c.stopContext == nil {
+		return context.Background()
+	}
+	return c.stopContext
+}
+
+// stopContextHandler bind ctx to every request which has no context yet
+func stopContextHandler(ctx context.Context) request.NamedHandler {
+	return request.NamedHandler{
+		Name: "vestack.StopContextHandler",
+		Fn: func(r *request.Request) {
+			if r.Context() == context.Background() {
+				r.SetContext(ctx)
+			}
+		},
+	}
+}
+
+// bindStopContext the sdk clients shared by the provider instances are copied with the handler of ctx, so the
+// requests of this client are cancelled with its own provider instance
+func (c *SdkClient) bindStopContext(ctx context.Context) {
+	c.stopContext = ctx
+	if ctx == nil {
+		return
+	}
+	handler := stopContextHandler(ctx)
+	bind := func(cli *client.Client) *client.Client {
+		copied := *cli
+		copied.Handlers = cli.Handlers.Copy()
+		copied.Handlers.Build.PushFrontNamed(handler)
+		return &copied
+	}
+	if c.VpcClient != nil {
+		c.VpcClient = &vpc.VPC{Client: bind(c.VpcClient.Client)}
+	}
+	if c.ClbClient != nil {
+		c.ClbClient = &clb.CLB{Client: bind(c.ClbClient.Client)}
+	}
+	if c.EcsClient != nil {
+		c.EcsClient = &ecs.ECS{Client: bind(c.EcsClient.Client)}
+	}
+	if c.EbsClient != nil {
+		c.EbsClient = &storageebs.STORAGEEBS{Client: bind(c.EbsClient.Client)}
+	}
+	if c.NatClient != nil {
+		c.NatClient = &natgateway.NATGATEWAY{Client: bind(c.NatClient.Client)}
+	}
+	if c.VpnClient != nil {
+		c.VpnClient = &vpn.VPN{Client: bind(c.VpnClient.Client)}
+	}
+	if c.AutoScalingClient != nil {
+		c.AutoScalingClient = &autoscaling.AUTOSCALING{Client: bind(c.AutoScalingClient.Client)}
+	}
+	if c.RdsClient != nil {
+		c.RdsClient = &rdsmysql.RDSMYSQL{Client: bind(c.RdsClient.Client)}
+	}
+	if c.RdsClientV2 != nil {
+		c.RdsClientV2 = &rdsmysqlv2.RDSMYSQLV2{Client: bind(c.RdsClientV2.Client)}
+	}
+	bindSession := func(sess *session.Session) *session.Session {
+		copied := &session.Session{Config: sess.Config, Handlers: sess.Handlers.Copy()}
+		copied.Handlers.Build.PushFrontNamed(handler)
+		return copied
+	}
+	if c.UniversalClient != nil {
+		c.UniversalClient = NewUniversalClient(bindSession(c.UniversalClient.Session), c.UniversalClient.overrides)
+	}
+	if c.BypassSvcClient != nil {
+		c.BypassSvcClient = NewBypassClient(bindSession(c.BypassSvcClient.Session), c.BypassSvcClient.overrides)
+	}
+}
+
+// WaitForStateContext same as StateChangeConf.WaitForState but return as soon as ctx is done,
+// the refresh of conf stop polling on the next tick
+func WaitForStateContext(ctx context.Context, conf *resource.StateChangeConf) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	refresh := conf.Refresh
+	conf.Refresh = func() (interface{}, string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		return refresh()
+	}
+
+	type result struct {
+		data interface{}
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		data, err := conf.WaitForState()
+		ch <- result{data: data, err: err}
+	}()
+	select {
+	case r := <-ch:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for state %v cancelled: %w", conf.Target, ctx.Err())
+	}
+}
+
+// RetryContext same as resource.Retry but stop retrying when ctx is done
+func RetryContext(ctx context.Context, timeout time.Duration, f resource.RetryFunc) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		if err := ctx.Err(); err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return f()
+	})
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CallProcessWithContext_Cancel(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := server.Config()
+	config.StopContext = ctx
+	client, err := config.Client()
+	assert.Nil(t, err)
+	svc := &mockVpcService{Client: client}
+
+	server.On("CreateVpc", MockResponse{Result: map[string]interface{}{"VpcId": "vpc-new"}})
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-new", "Status": "Pending"},
+	}}})
+
+	r := mockVpcResource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"cidr_block": "172.16.0.0/16",
+	})
+	callbacks := svc.CreateResource(d, r)
+	call := callbacks[0].Call
+	call.LockId = func(d *schema.ResourceData) string {
+		return "mock-vpc-lock"
+	}
+	assert.Nil(t, call.InitWriteCall(d, r, false))
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	err = CallProcess([]SdkCall{call}, d, client, svc)
+	assert.NotNil(t, err)
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.True(t, time.Since(start) < 10*time.Second)
+
+	// lock is released when cancelled
+	assert.Nil(t, TryLockContext(context.Background(), "mock-vpc-lock"))
+	ReleaseLock("mock-vpc-lock")
+
+	// cancelled context stop before any request
+	count := len(server.Requests(""))
+	err = DefaultDispatcher().Delete(svc, d, r)
+	assert.NotNil(t, err)
+	assert.Equal(t, count, len(server.Requests("")))
+}
+
+func Test_TryLockContext(t *testing.T) {
+	TryLock("mock-lock")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := TryLockContext(ctx, "mock-lock")
+	assert.NotNil(t, err)
+	ReleaseLock("mock-lock")
+	assert.Nil(t, TryLockContext(context.Background(), "mock-lock"))
+	ReleaseLock("mock-lock")
+}
+
+func Test_Acquire_Cancel(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	config := server.Config()
+	config.StopContext = ctx
+	config.MaxSyncConcurrency = 1
+	client, err := config.Client()
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.Acquire())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	assert.NotNil(t, client.Acquire())
+	assert.True(t, time.Since(start) < 5*time.Second)
+	client.Release()
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/credentials"
+)
+
+const (
+	assumeRoleDefaultDuration = 3600
+	// assumeRoleExpiryWindow refresh the session token before it expires
+	assumeRoleExpiryWindow = 5 * time.Minute
+)
+
+// AssumeRole provider assume_role block, the credentials are refreshed by sts before expired
+type AssumeRole struct {
+	RoleTrn         string
+	SessionName     string
+	DurationSeconds int
+	Policy          string
+}
+
+// baseCredentials static access_key/secret_key, then VOLCSTACK_* env, then shared credentials file
+func (c *Config) baseCredentials() *credentials.Credentials {
+	var providers []credentials.Provider
+	if c.AccessKey != "" || c.SecretKey != "" {
+		providers = append(providers, &credentials.StaticProvider{Value: credentials.Value{
+			AccessKeyID:     c.AccessKey,
+			SecretAccessKey: c.SecretKey,
+			SessionToken:    c.SessionToken,
+			ProviderName:    credentials.StaticProviderName,
+		}})
+	}
+	providers = append(providers,
+		&credentials.EnvProvider{},
+		&credentials.SharedCredentialsProvider{
+			Filename: c.SharedCredentialsFile,
+			Profile:  c.Profile,
+		},
+	)
+	return credentials.NewCredentials(&credentials.ChainProvider{
+		Providers:     providers,
+		VerboseErrors: true,
+	})
+}
+
+type assumeRoleProvider struct {
+	credentials.Expiry
+	client *Universal
+	role   AssumeRole
+}
+
+func newAssumeRoleCredentials(client *Universal, role AssumeRole) *credentials.Credentials {
+	if role.DurationSeconds == 0 {
+		role.DurationSeconds = assumeRoleDefaultDuration
+	}
+	if role.SessionName == "" {
+		role.SessionName = fmt.Sprintf("terraform-%d", time.Now().Unix())
+	}
+	return credentials.NewExpireAbleCredentials(&assumeRoleProvider{
+		client: client,
+		role:   role,
+	})
+}
+
+func (p *assumeRoleProvider) Retrieve() (credentials.Value, error) {
+	param := map[string]interface{}{
+		"RoleTrn":         p.role.RoleTrn,
+		"RoleSessionName": p.role.SessionName,
+		"DurationSeconds": p.role.DurationSeconds,
+	}
+	if p.role.Policy != "" {
+		param["Policy"] = p.role.Policy
+	}
+	resp, err := p.client.DoCall(UniversalInfo{
+		ServiceName: "sts",
+		Action:      "AssumeRole",
+		Version:     "2018-01-01",
+		HttpMethod:  GET,
+	}, &param)
+	if err != nil {
+		return credentials.Value{}, fmt.Errorf("assume role %s error: %w", p.role.RoleTrn, err)
+	}
+
+	value := credentials.Value{ProviderName: "AssumeRoleProvider"}
+	for field, target := range map[string]*string{
+		"Result.Credentials.AccessKeyId":     &value.AccessKeyID,
+		"Result.Credentials.SecretAccessKey": &value.SecretAccessKey,
+		"Result.Credentials.SessionToken":    &value.SessionToken,
+	} {
+		v, _ := ObtainSdkValue(field, *resp)
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return credentials.Value{}, fmt.Errorf("assume role %s error: %s is empty", p.role.RoleTrn, field)
+		}
+		*target = s
+	}
+
+	expiration := time.Now().Add(time.Duration(p.role.DurationSeconds) * time.Second)
+	v, _ := ObtainSdkValue("Result.Credentials.ExpiredTime", *resp)
+	switch t := v.(type) {
+	case string:
+		if e, err := time.Parse(time.RFC3339, t); err == nil {
+			expiration = e
+		}
+	case float64:
+		// epoch seconds
+		expiration = time.Unix(int64(t), 0)
+	}
+	p.SetExpiration(expiration, assumeRoleExpiryWindow)
+	logger.Info("assume role %s, credentials expire at %s", p.role.RoleTrn, expiration.Format(time.RFC3339))
+	return value, nil
+}
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockSignedAccessKey(req *MockRequest) string {
+	authorization := req.Header.Get("Authorization")
+	credential := authorization[strings.Index(authorization, "Credential=")+len("Credential="):]
+	return credential[:strings.Index(credential, "/")]
+}
+
+func Test_AssumeRoleCredentials(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+
+	var count int
+	server.OnFunc("AssumeRole", func(req *MockRequest) *MockResponse {
+		count++
+		// the first token is already in the refresh window
+		expired := time.Now().Add(time.Minute)
+		if count > 1 {
+			expired = time.Now().Add(time.Hour)
+		}
+		return &MockResponse{Result: map[string]interface{}{
+			"Credentials": map[string]interface{}{
+				"AccessKeyId":     fmt.Sprintf("assumed-ak-%d", count),
+				"SecretAccessKey": "assumed-sk",
+				"SessionToken":    "assumed-token",
+				"ExpiredTime":     expired.Format(time.RFC3339),
+			},
+		}}
+	})
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	config := server.Config()
+	config.AssumeRole = &AssumeRole{
+		RoleTrn:     "trn:iam::2100000000:role/terraform",
+		SessionName: "mock",
+		Policy:      `{"Statement":[]}`,
+	}
+	client, err := config.Client()
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+		assert.Nil(t, err)
+	}
+
+	assumeRequests := server.Requests("AssumeRole")
+	assert.Equal(t, 2, len(assumeRequests))
+	assert.Equal(t, "sts", assumeRequests[0].Service)
+	assert.Equal(t, "mock-access-key", mockSignedAccessKey(assumeRequests[0]))
+	assert.Equal(t, "trn:iam::2100000000:role/terraform", assumeRequests[0].Params["RoleTrn"])
+	assert.Equal(t, "3600", assumeRequests[0].Params["DurationSeconds"])
+	assert.Equal(t, `{"Statement":[]}`, assumeRequests[0].Params["Policy"])
+
+	vpcRequests := server.Requests("DescribeVpcs")
+	assert.Equal(t, "assumed-ak-2", mockSignedAccessKey(vpcRequests[0]))
+	assert.Equal(t, "assumed-ak-2", mockSignedAccessKey(vpcRequests[1]))
+	assert.Equal(t, "assumed-token", vpcRequests[0].Header.Get("X-Security-Token"))
+}
+
+func Test_AssumeRoleCredentials_EpochExpiredTime(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	server.On("AssumeRole", MockResponse{Result: map[string]interface{}{
+		"Credentials": map[string]interface{}{
+			"AccessKeyId":     "assumed-ak",
+			"SecretAccessKey": "assumed-sk",
+			"SessionToken":    "assumed-token",
+			"ExpiredTime":     time.Now().Add(time.Hour).Unix(),
+		},
+	}})
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	config := server.Config()
+	config.AssumeRole = &AssumeRole{RoleTrn: "trn:iam::2100000000:role/epoch", SessionName: "mock"}
+	client, err := config.Client()
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1, len(server.Requests("AssumeRole")))
+	assert.Equal(t, "assumed-ak", mockSignedAccessKey(server.Requests("DescribeVpcs")[0]))
+}
+
+func Test_SharedCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "credentials")
+	err = ioutil.WriteFile(file, []byte("[ci]\nvolcengine_access_key_id = profile-ak\nvolcengine_secret_access_key = profile-sk\n"), 0600)
+	assert.Nil(t, err)
+
+	server := NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	config := server.Config()
+	config.AccessKey = ""
+	config.SecretKey = ""
+	config.SharedCredentialsFile = file
+	config.Profile = "ci"
+	client, err := config.Client()
+	assert.Nil(t, err)
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "profile-ak", mockSignedAccessKey(server.Requests("DescribeVpcs")[0]))
+
+	config.Profile = "missing"
+	_, err = config.Client()
+	assert.NotNil(t, err)
+}
@@ -46,14 +46,39 @@ func DataSourceToRequest(d *schema.ResourceData, r *schema.Resource, info DataSo
 			Ignore: true,
 		}
 	}
+	if _, ok := info.RequestConverts[OutputFormatField]; !ok {
+		info.RequestConverts[OutputFormatField] = RequestConvert{
+			Ignore: true,
+		}
+	}
+	if _, ok := info.RequestConverts[FilterField]; !ok {
+		info.RequestConverts[FilterField] = RequestConvert{
+			Ignore: true,
+		}
+	}
+	// the api without TagFilters, tags are filtered by ResponseToDataSource
+	if _, ok := info.RequestConverts[TagsField]; !ok && tagFilterEnabled(r) {
+		info.RequestConverts[TagsField] = RequestConvert{
+			Ignore: true,
+		}
+	}
 	return ResourceDateToRequest(d, r, false, info.RequestConverts, RequestConvertAll, info.ContentType)
 }
 
 func ResponseToDataSource(d *schema.ResourceData, r *schema.Resource, info DataSourceInfo, collection []interface{}) (err error) {
 	var (
-		result []map[string]interface{}
+		result  []map[string]interface{}
+		filters []dataSourceFilter
 	)
 
+	if clientTagFilter(r, info) {
+		collection = filterByTags(d, collection)
+	}
+	if filterEnabled(r) {
+		if filters, err = expandDataSourceFilters(d); err != nil {
+			return err
+		}
+	}
 	for _, item := range collection {
 		var (
 			temp map[string]interface{}
@@ -71,6 +96,9 @@ func ResponseToDataSource(d *schema.ResourceData, r *schema.Resource, info DataS
 			result = append(result, item.(map[string]interface{}))
 		}
 	}
+	if len(filters) > 0 {
+		result = filterDataSource(r, info, result, filters)
+	}
 	_, _, err = datasourceMapping(d, result, dataSource{
 		idField: info.IdField,
 		idValue: func(idField string, item map[string]interface{}) string {
@@ -80,10 +108,106 @@ func ResponseToDataSource(d *schema.ResourceData, r *schema.Resource, info DataS
 			return mergeDatasource(r, info.CollectField, item, info.ResponseConverts)
 		},
 		targetName: info.CollectField,
+		elem:       collectElem(r, info.CollectField),
 	})
 	return err
 }
 
+// TagFilterConvert the `tags` argument of data source to TagFilters.N.Key and TagFilters.N.Values.1 of api
+func TagFilterConvert() RequestConvert {
+	return RequestConvert{
+		TargetField: "TagFilters",
+		ConvertType: ConvertListN,
+		NextLevelConvert: map[string]RequestConvert{
+			"value": {
+				TargetField: "Values.1",
+			},
+		},
+	}
+}
+
+// WrapDataSource add the common arguments to data source, it is applied to the registered data sources
+// and again by Dispatcher.Data because the Read functions pass a new resource
+func WrapDataSource(resource *schema.Resource) *schema.Resource {
+	DataSourceTagFilter(resource)
+	DataSourceFilter(resource)
+	DataSourceOutputFormat(resource)
+	return resource
+}
+
+// DataSourceTagFilter add the optional `tags` argument to data source whose results have tags.
+// It is sent as TagFilters when the DataSourceInfo converts it, otherwise the results are filtered by their Tags
+func DataSourceTagFilter(resource *schema.Resource) *schema.Resource {
+	if _, ok := resource.Schema[TagsField]; ok {
+		return resource
+	}
+	for _, v := range resource.Schema {
+		if v.Type != schema.TypeList || !v.Computed {
+			continue
+		}
+		if elem, ok := v.Elem.(*schema.Resource); ok {
+			if _, ok = elem.Schema[TagsField]; ok {
+				tags := TagsSchema()
+				tags.Description = "Tags used to filter the results."
+				resource.Schema[TagsField] = tags
+				return resource
+			}
+		}
+	}
+	return resource
+}
+
+func tagFilterEnabled(r *schema.Resource) bool {
+	if r == nil {
+		return false
+	}
+	tags, ok := r.Schema[TagsField]
+	return ok && tags.Type == schema.TypeSet && tags.Optional
+}
+
+func clientTagFilter(r *schema.Resource, info DataSourceInfo) bool {
+	if !tagFilterEnabled(r) {
+		return false
+	}
+	convert, ok := info.RequestConverts[TagsField]
+	return !ok || convert.Ignore
+}
+
+// filterByTags keep the results which have all tags of the `tags` argument
+func filterByTags(d *schema.ResourceData, collection []interface{}) []interface{} {
+	filters := tagsToMap(d.Get(TagsField))
+	if len(filters) == 0 {
+		return collection
+	}
+	var result []interface{}
+	for _, item := range collection {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tags := make(map[string]interface{})
+		list, _ := m["Tags"].([]interface{})
+		for _, tag := range list {
+			if t, ok := tag.(map[string]interface{}); ok {
+				if key, ok := t["Key"].(string); ok {
+					tags[key] = t["Value"]
+				}
+			}
+		}
+		matched := true
+		for key, value := range filters {
+			if v, ok := tags[key]; !ok || v != value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 type sliceValueFunc func(map[string]interface{}) map[string]interface{}
 
 type idValueFunc func(string, map[string]interface{}) string
@@ -93,6 +217,8 @@ type dataSource struct {
 	idValue    idValueFunc
 	sliceValue sliceValueFunc
 	targetName string
+	// elem the schema of the items of targetName, Sensitive fields are redacted in output_file
+	elem *schema.Resource
 }
 
 func mapMapping(result interface{}, ds dataSource) (map[string]interface{}, error) {
@@ -244,7 +370,8 @@ func datasourceMapping(d *schema.ResourceData, result interface{}, datasource da
 				return nil, nil, err
 			}
 			if outputFile, ok := d.GetOk("output_file"); ok && outputFile.(string) != "" {
-				err = writeToFile(outputFile.(string), data)
+				format, _ := d.Get(OutputFormatField).(string)
+				err = writeOutputFile(outputFile.(string), format, redactSensitive(datasource.elem, data))
 				if err != nil {
 					return nil, nil, err
 				}
@@ -284,7 +411,7 @@ func writeToFile(filePath string, data interface{}) error {
 		}
 	}
 
-	return ioutil.WriteFile(absPath, bs, 0422)
+	return ioutil.WriteFile(absPath, bs, 0644)
 }
 
 func absolutePath(filePath string) (string, error) {
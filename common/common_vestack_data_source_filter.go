@@ -0,0 +1,218 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	FilterField = "filter"
+
+	FilterModeExact = "exact"
+	FilterModeRegex = "regex"
+	FilterModeGlob  = "glob"
+)
+
+func FilterSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Filter the results on the client side. The results must match all filters.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringMatch(filterNamePattern, "must be attribute names joined with `.`"),
+					Description:  "The name of the attribute of the result, such as `status`. Nested attributes are joined with `.`, such as `tags.key`.",
+				},
+				"values": {
+					Type:     schema.TypeList,
+					Required: true,
+					MinItems: 1,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Description: "The values of the attribute, the result matches when any value matches.",
+				},
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      FilterModeExact,
+					ValidateFunc: validation.StringInSlice([]string{FilterModeExact, FilterModeRegex, FilterModeGlob}, false),
+					Description:  "The match mode of the values. Valid values: `exact`, `regex`, `glob`. Default is `exact`.",
+				},
+			},
+		},
+	}
+}
+
+// DataSourceFilter add the `filter` block to data source, it is applied by ResponseToDataSource
+func DataSourceFilter(resource *schema.Resource) *schema.Resource {
+	if _, ok := resource.Schema[FilterField]; ok {
+		return resource
+	}
+	if _, ok := resource.Schema["output_file"]; !ok {
+		return resource
+	}
+	resource.Schema[FilterField] = FilterSchema()
+	return resource
+}
+
+var filterNamePattern = regexp.MustCompile(`^[A-Za-z0-9]+(_[A-Za-z0-9]+)*(\.[A-Za-z0-9]+(_[A-Za-z0-9]+)*)*$`)
+
+type dataSourceFilter struct {
+	path  []string
+	match func(string) bool
+}
+
+func filterEnabled(r *schema.Resource) bool {
+	if r == nil {
+		return false
+	}
+	filter, ok := r.Schema[FilterField]
+	if !ok || filter.Type != schema.TypeList || !filter.Optional {
+		return false
+	}
+	_, ok = filter.Elem.(*schema.Resource)
+	return ok
+}
+
+func expandDataSourceFilters(d *schema.ResourceData) ([]dataSourceFilter, error) {
+	var filters []dataSourceFilter
+	for _, v := range d.Get(FilterField).([]interface{}) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, value := range m["values"].([]interface{}) {
+			s, _ := value.(string)
+			values = append(values, s)
+		}
+		match, err := filterMatcher(m["mode"].(string), values)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, dataSourceFilter{
+			path:  strings.Split(m["name"].(string), "."),
+			match: match,
+		})
+	}
+	return filters, nil
+}
+
+func filterMatcher(mode string, values []string) (func(string) bool, error) {
+	var patterns []*regexp.Regexp
+	switch mode {
+	case FilterModeRegex:
+		for _, v := range values {
+			p, err := regexp.Compile(v)
+			if err != nil {
+				return nil, fmt.Errorf("filter value %s is not a valid regex: %w", v, err)
+			}
+			patterns = append(patterns, p)
+		}
+	case FilterModeGlob:
+		for _, v := range values {
+			p := regexp.QuoteMeta(v)
+			p = strings.Replace(p, `\*`, ".*", -1)
+			p = strings.Replace(p, `\?`, ".", -1)
+			patterns = append(patterns, regexp.MustCompile("^"+p+"$"))
+		}
+	case FilterModeExact, "":
+		return func(s string) bool {
+			for _, v := range values {
+				if v == s {
+					return true
+				}
+			}
+			return false
+		}, nil
+	default:
+		return nil, fmt.Errorf("filter mode %s is not supported", mode)
+	}
+	return func(s string) bool {
+		for _, p := range patterns {
+			if p.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// filterDataSource keep the items match all filters, the attribute is read from the result of data source
+// and then from the response of api
+func filterDataSource(r *schema.Resource, info DataSourceInfo, items []map[string]interface{}, filters []dataSourceFilter) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, item := range items {
+		converted := mergeDatasource(r, info.CollectField, item, info.ResponseConverts)
+		matched := true
+		for _, f := range filters {
+			if !f.matched(converted, item) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// matched the item matches when any value of the attribute matches, lists are flattened at every level
+func (f dataSourceFilter) matched(items ...interface{}) bool {
+	for _, item := range items {
+		for _, v := range filterValues(item, f.path) {
+			if f.match(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterValues the values of path in v, the key of path is the attribute of schema or the field of response
+func filterValues(v interface{}, path []string) []string {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var result []string
+		for _, e := range value {
+			result = append(result, filterValues(e, path)...)
+		}
+		return result
+	case []map[string]interface{}:
+		var result []string
+		for _, e := range value {
+			result = append(result, filterValues(e, path)...)
+		}
+		return result
+	case *schema.Set:
+		return filterValues(value.List(), path)
+	case map[string]interface{}:
+		if len(path) == 0 {
+			return nil
+		}
+		next, ok := value[path[0]]
+		if !ok {
+			next, ok = value[DownLineToHump(path[0])]
+		}
+		if !ok {
+			return nil
+		}
+		return filterValues(next, path[1:])
+	default:
+		if len(path) > 0 {
+			return nil
+		}
+		return []string{fmt.Sprintf("%v", value)}
+	}
+}
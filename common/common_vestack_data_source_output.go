@@ -0,0 +1,232 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputFormatField = "output_format"
+
+	OutputFormatJson      = "json"
+	OutputFormatJsonLines = "jsonl"
+	OutputFormatCsv       = "csv"
+	OutputFormatYaml      = "yaml"
+)
+
+func OutputFormatSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      OutputFormatJson,
+		ValidateFunc: validation.StringInSlice([]string{OutputFormatJson, OutputFormatJsonLines, OutputFormatCsv, OutputFormatYaml}, false),
+		Description: "The format of output_file. Valid values: `json`, `jsonl`, `csv`, `yaml`. Default is `json`. " +
+			"The nested attributes are flattened to columns joined with `.` in csv.",
+	}
+}
+
+// DataSourceOutputFormat add the `output_format` to data source with output_file
+func DataSourceOutputFormat(resource *schema.Resource) *schema.Resource {
+	if _, ok := resource.Schema[OutputFormatField]; ok {
+		return resource
+	}
+	if _, ok := resource.Schema["output_file"]; !ok {
+		return resource
+	}
+	resource.Schema[OutputFormatField] = OutputFormatSchema()
+	return resource
+}
+
+func collectElem(r *schema.Resource, collectField string) *schema.Resource {
+	if r == nil {
+		return nil
+	}
+	for _, key := range strings.Split(collectField, ".") {
+		s, ok := r.Schema[key]
+		if !ok {
+			return nil
+		}
+		elem, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			return nil
+		}
+		r = elem
+	}
+	return r
+}
+
+// redactSensitive copy the data with the Sensitive fields of elem replaced
+func redactSensitive(elem *schema.Resource, data []map[string]interface{}) []map[string]interface{} {
+	if elem == nil {
+		return data
+	}
+	result := make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		result = append(result, redactSensitiveMap(elem, item))
+	}
+	return result
+}
+
+func redactSensitiveMap(elem *schema.Resource, item map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		s, ok := elem.Schema[k]
+		if !ok {
+			result[k] = v
+			continue
+		}
+		if s.Sensitive {
+			result[k] = logger.Redacted
+			continue
+		}
+		next, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			result[k] = v
+			continue
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			result[k] = redactSensitiveMap(next, value)
+		case []map[string]interface{}:
+			result[k] = redactSensitive(next, value)
+		case []interface{}:
+			list := make([]interface{}, len(value))
+			for i, e := range value {
+				if m, ok := e.(map[string]interface{}); ok {
+					list[i] = redactSensitiveMap(next, m)
+				} else {
+					list[i] = e
+				}
+			}
+			result[k] = list
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func writeOutputFile(filePath string, format string, data []map[string]interface{}) error {
+	var (
+		bs  []byte
+		err error
+	)
+	switch format {
+	case OutputFormatJson, "":
+		return writeToFile(filePath, data)
+	case OutputFormatJsonLines:
+		var buf bytes.Buffer
+		for _, item := range data {
+			line, err := json.Marshal(item)
+			if err != nil {
+				return fmt.Errorf("Marshal data %#v and got an error: %#v", item, err)
+			}
+			buf.Write(line)
+			buf.WriteString("\n")
+		}
+		bs = buf.Bytes()
+	case OutputFormatCsv:
+		bs, err = marshalCsv(data)
+	case OutputFormatYaml:
+		bs, err = yaml.Marshal(normalizeOutput(data))
+	default:
+		return fmt.Errorf("output_format %s is not supported", format)
+	}
+	if err != nil {
+		return err
+	}
+	absPath, err := absolutePath(filePath)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(absPath)
+	return ioutil.WriteFile(absPath, bs, 0644)
+}
+
+// normalizeOutput the values of data source are the types of schema, round trip by json so that
+// yaml writes them as json
+func normalizeOutput(data interface{}) interface{} {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var result interface{}
+	if err = json.Unmarshal(bs, &result); err != nil {
+		return data
+	}
+	return result
+}
+
+// marshalCsv one row for every item, the columns are the flattened attributes of all items
+func marshalCsv(data []map[string]interface{}) ([]byte, error) {
+	var (
+		rows    []map[string]string
+		columns []string
+	)
+	exist := make(map[string]bool)
+	for _, item := range data {
+		row := make(map[string]string)
+		flattenOutput("", normalizeOutput(item), row)
+		for k := range row {
+			if !exist[k] {
+				exist[k] = true
+				columns = append(columns, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = row[c]
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// flattenOutput nested maps and lists to keys joined with `.`, such as `tags.0.key`
+func flattenOutput(prefix string, v interface{}, row map[string]string) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			flattenOutput(join(k), e, row)
+		}
+	case []interface{}:
+		for i, e := range value {
+			flattenOutput(join(strconv.Itoa(i)), e, row)
+		}
+	case nil:
+		row[prefix] = ""
+	default:
+		row[prefix] = fmt.Sprintf("%v", value)
+	}
+}
@@ -0,0 +1,189 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockVpcDataSource() *schema.Resource {
+	return DataSourceTagFilter(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"output_file": {Type: schema.TypeString, Optional: true},
+			"total_count": {Type: schema.TypeInt, Computed: true},
+			"vpcs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vpc_id": {Type: schema.TypeString, Computed: true},
+						"tags":   TagsSchemaComputed(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func Test_DataSourceTagFilter_Client(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-1", "Tags": []interface{}{
+			map[string]interface{}{"Key": "env", "Value": "dev"},
+			map[string]interface{}{"Key": "app", "Value": "web"},
+		}},
+		map[string]interface{}{"VpcId": "vpc-2", "Tags": []interface{}{
+			map[string]interface{}{"Key": "env", "Value": "prod"},
+		}},
+	}}}, MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+	r := mockVpcDataSource()
+	assert.True(t, r.Schema["tags"].Optional)
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+		},
+	})
+	assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+	assert.Equal(t, 1, d.Get("total_count"))
+	assert.Equal(t, "vpc-1", d.Get("vpcs.0.vpc_id"))
+	assert.Nil(t, server.Requests("DescribeVpcs")[0].Params["TagFilters.1.Key"])
+}
+
+type mockTagFilterVpcService struct {
+	mockVpcService
+}
+
+func (s *mockTagFilterVpcService) DatasourceResources(d *schema.ResourceData, r *schema.Resource) DataSourceInfo {
+	info := s.mockVpcService.DatasourceResources(d, r)
+	info.RequestConverts = map[string]RequestConvert{
+		"tags": TagFilterConvert(),
+	}
+	return info
+}
+
+func Test_DataSourceTagFilter_Request(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-1"},
+	}}})
+
+	r := mockVpcDataSource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+		},
+	})
+	assert.Nil(t, DefaultDispatcher().Data(&mockTagFilterVpcService{mockVpcService: *vpc}, d, r))
+	// filtered by the api, the results are kept as is
+	assert.Equal(t, 1, d.Get("total_count"))
+	requests := server.Requests("DescribeVpcs")
+	assert.Equal(t, "env", requests[0].Params["TagFilters.1.Key"])
+	assert.Equal(t, "dev", requests[0].Params["TagFilters.1.Values.1"])
+}
+
+func Test_DataSourceFilter(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+
+	r := DataSourceFilter(mockVpcDataSource())
+	cases := []struct {
+		filter []interface{}
+		ids    []string
+	}{
+		{
+			filter: []interface{}{
+				map[string]interface{}{"name": "vpc_id", "values": []interface{}{"vpc-2"}},
+			},
+			ids: []string{"vpc-2"},
+		},
+		{
+			// nested attribute of the result
+			filter: []interface{}{
+				map[string]interface{}{"name": "tags.value", "values": []interface{}{"^d"}, "mode": "regex"},
+			},
+			ids: []string{"vpc-1"},
+		},
+		{
+			// the field of response which is not in the schema of data source
+			filter: []interface{}{
+				map[string]interface{}{"name": "status", "values": []interface{}{"Avail*"}, "mode": "glob"},
+			},
+			ids: []string{"vpc-1"},
+		},
+		{
+			filter: []interface{}{
+				map[string]interface{}{"name": "vpc_id", "values": []interface{}{"vpc-?"}, "mode": "glob"},
+				map[string]interface{}{"name": "tags.key", "values": []interface{}{"app"}},
+			},
+			ids: nil,
+		},
+	}
+	for _, c := range cases {
+		server.Reset()
+		server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-1", "Status": "Available", "Tags": []interface{}{
+				map[string]interface{}{"Key": "env", "Value": "dev"},
+			}},
+			map[string]interface{}{"VpcId": "vpc-2", "Status": "Pending", "Tags": []interface{}{
+				map[string]interface{}{"Key": "env", "Value": "prod"},
+			}},
+		}}}, MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"filter": c.filter,
+		})
+		assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+		var ids []string
+		for _, v := range d.Get("vpcs").([]interface{}) {
+			ids = append(ids, v.(map[string]interface{})["vpc_id"].(string))
+		}
+		assert.Equal(t, c.ids, ids)
+		assert.Nil(t, server.Requests("DescribeVpcs")[0].Params["Filter.1.Name"])
+	}
+}
+
+func Test_DataSourceOutputFormat(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "output")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	r := DataSourceOutputFormat(mockVpcDataSource())
+	r.Schema["vpcs"].Elem.(*schema.Resource).Schema["secret"] = &schema.Schema{Type: schema.TypeString, Computed: true, Sensitive: true}
+	cases := map[string]string{
+		"json":  "[\n\t{\n\t\t\"secret\": \"******\",\n\t\t\"tags\": [\n\t\t\t{\n\t\t\t\t\"key\": \"env\",\n\t\t\t\t\"value\": \"dev\"\n\t\t\t}\n\t\t],\n\t\t\"vpc_id\": \"vpc-1\"\n\t}\n]",
+		"jsonl": "{\"secret\":\"******\",\"tags\":[{\"key\":\"env\",\"value\":\"dev\"}],\"vpc_id\":\"vpc-1\"}\n",
+		"csv":   "secret,tags.0.key,tags.0.value,vpc_id\n******,env,dev,vpc-1\n",
+		"yaml":  "- secret: '******'\n  tags:\n    - key: env\n      value: dev\n  vpc_id: vpc-1\n",
+	}
+	for format, expected := range cases {
+		server.Reset()
+		server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-1", "Secret": "s3cr3t", "Tags": []interface{}{
+				map[string]interface{}{"Key": "env", "Value": "dev"},
+			}},
+		}}})
+		file := filepath.Join(dir, "vpcs."+format)
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"output_file":   file,
+			"output_format": format,
+		})
+		assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+		// the state keeps the value, only the file is redacted
+		assert.Equal(t, "s3cr3t", d.Get("vpcs.0.secret"))
+		bs, err := ioutil.ReadFile(file)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(bs), format)
+		info, err := os.Stat(file)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	}
+}
@@ -0,0 +1,253 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	TagsField    = "tags"
+	TagsAllField = "tags_all"
+)
+
+// IgnoreTags provider ignore_tags block, the tags are never read into state and never removed
+type IgnoreTags struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+func (i IgnoreTags) ignored(key string) bool {
+	for _, k := range i.Keys {
+		if k == key {
+			return true
+		}
+	}
+	for _, p := range i.KeyPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTags the default_tags of provider
+func (c *SdkClient) DefaultTags() map[string]string {
+	if c == nil || c.config == nil {
+		return nil
+	}
+	return c.config.DefaultTags
+}
+
+// IgnoreTags the ignore_tags of provider
+func (c *SdkClient) IgnoreTags() IgnoreTags {
+	if c == nil || c.config == nil {
+		return IgnoreTags{}
+	}
+	return c.config.IgnoreTags
+}
+
+// TagsEnabled the resource has the optional tags set of TagsSchema
+func TagsEnabled(resource *schema.Resource) bool {
+	tags, ok := resource.Schema[TagsField]
+	if !ok || tags.Type != schema.TypeSet || !tags.Optional {
+		return false
+	}
+	elem, ok := tags.Elem.(*schema.Resource)
+	if !ok || len(elem.Schema) != 2 {
+		return false
+	}
+	_, key := elem.Schema["key"]
+	_, value := elem.Schema["value"]
+	return key && value
+}
+
+// ResourceDefaultTags add the computed `tags_all` to resource with tags. The default_tags of provider are merged into
+// the tags of create and update, tags_all is all the tags of the resource, tags only keeps the tags of configuration.
+// The ignore_tags of provider are dropped from both
+func ResourceDefaultTags(resource *schema.Resource) *schema.Resource {
+	if !TagsEnabled(resource) {
+		return resource
+	}
+	if _, ok := resource.Schema[TagsAllField]; ok {
+		return resource
+	}
+	tagsAll := TagsSchemaComputed()
+	tagsAll.Description = "All tags of the resource, include the default_tags of provider."
+	resource.Schema[TagsAllField] = tagsAll
+
+	if create := resource.Create; create != nil {
+		resource.Create = func(d *schema.ResourceData, meta interface{}) error {
+			prior := tagsToMap(d.Get(TagsField))
+			all := mergeDefaultTags(d, meta)
+			if err := d.Set(TagsField, all); err != nil {
+				return err
+			}
+			if err := d.Set(TagsAllField, all); err != nil {
+				return err
+			}
+			if err := create(d, meta); err != nil {
+				return err
+			}
+			return refreshTags(d, meta, prior)
+		}
+	}
+	if read := resource.Read; read != nil {
+		resource.Read = func(d *schema.ResourceData, meta interface{}) error {
+			prior := tagsToMap(d.Get(TagsField))
+			if err := read(d, meta); err != nil {
+				return err
+			}
+			return refreshTags(d, meta, prior)
+		}
+	}
+	if update := resource.Update; update != nil {
+		resource.Update = func(d *schema.ResourceData, meta interface{}) error {
+			prior := tagsToMap(d.Get(TagsField))
+			all := mergeDefaultTags(d, meta)
+			if err := d.Set(TagsField, all); err != nil {
+				return err
+			}
+			// GetTagsDifference compare it with tags_all of state
+			if err := d.Set(TagsAllField, all); err != nil {
+				return err
+			}
+			if err := update(d, meta); err != nil {
+				return err
+			}
+			return refreshTags(d, meta, prior)
+		}
+	}
+
+	customizeDiff := resource.CustomizeDiff
+	updatable := resource.Update != nil
+	resource.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+		if updatable || diff.Id() == "" {
+			if err := diffTagsAll(diff, meta); err != nil {
+				return err
+			}
+		}
+		if customizeDiff != nil {
+			return customizeDiff(diff, meta)
+		}
+		return nil
+	}
+	return resource
+}
+
+// GetTagsDifference the tags to add and remove on update. When the resource has tags_all,
+// the default_tags of provider are included and the ignore_tags are never removed
+func GetTagsDifference(d *schema.ResourceData) (add *schema.Set, remove *schema.Set) {
+	o, _ := d.GetChange(TagsAllField)
+	oldAll, ok := o.(*schema.Set)
+	if !ok {
+		add, remove, _, _ = GetSetDifference(TagsField, d, TagsHash, false)
+		return add, remove
+	}
+	if oldAll.Len() == 0 {
+		// tags_all is not refreshed into state yet
+		o, _ = d.GetChange(TagsField)
+		oldAll = o.(*schema.Set)
+	}
+	newAll := d.Get(TagsAllField).(*schema.Set)
+	add = newAll.Difference(oldAll)
+	added := tagsToMap(add)
+	remove = schema.NewSet(TagsHash, nil)
+	for _, tag := range oldAll.Difference(newAll).List() {
+		if _, ok := added[tag.(map[string]interface{})["key"].(string)]; !ok {
+			remove.Add(tag)
+		}
+	}
+	return add, remove
+}
+
+// mergeDefaultTags default_tags overridden by tags of the resource
+func mergeDefaultTags(d *schema.ResourceData, meta interface{}) *schema.Set {
+	client, _ := meta.(*SdkClient)
+	all := make(map[string]string)
+	for k, v := range client.DefaultTags() {
+		all[k] = v
+	}
+	for k, v := range tagsToMap(d.Get(TagsField)) {
+		all[k] = v
+	}
+	return mapToTags(all)
+}
+
+// refreshTags tags_all is the tags read from api without ignored ones, a default tag is kept in tags
+// only when it was in tags before, otherwise it is only in tags_all
+func refreshTags(d *schema.ResourceData, meta interface{}, prior map[string]string) error {
+	if d.Id() == "" {
+		return nil
+	}
+	client, _ := meta.(*SdkClient)
+	ignore := client.IgnoreTags()
+	defaults := client.DefaultTags()
+	all := make(map[string]string)
+	tags := make(map[string]string)
+	for k, v := range tagsToMap(d.Get(TagsField)) {
+		if ignore.ignored(k) {
+			continue
+		}
+		all[k] = v
+		if dv, ok := defaults[k]; ok && dv == v {
+			if pv, ok := prior[k]; !ok || pv != v {
+				continue
+			}
+		}
+		tags[k] = v
+	}
+	if err := d.Set(TagsField, mapToTags(tags)); err != nil {
+		return err
+	}
+	return d.Set(TagsAllField, mapToTags(all))
+}
+
+// diffTagsAll plan the change of tags_all, update is triggered when only default_tags changed
+func diffTagsAll(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown(TagsField) {
+		return diff.SetNewComputed(TagsAllField)
+	}
+	client, _ := meta.(*SdkClient)
+	all := make(map[string]string)
+	for k, v := range client.DefaultTags() {
+		all[k] = v
+	}
+	for k, v := range tagsToMap(diff.Get(TagsField)) {
+		all[k] = v
+	}
+	planned := mapToTags(all)
+	if old, ok := diff.Get(TagsAllField).(*schema.Set); ok && diff.Id() != "" && old.Equal(planned) {
+		return nil
+	}
+	return diff.SetNew(TagsAllField, planned)
+}
+
+func tagsToMap(v interface{}) map[string]string {
+	result := make(map[string]string)
+	set, ok := v.(*schema.Set)
+	if !ok || set == nil {
+		return result
+	}
+	for _, tag := range set.List() {
+		m, ok := tag.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _ := m["key"].(string)
+		value, _ := m["value"].(string)
+		result[key] = value
+	}
+	return result
+}
+
+func mapToTags(tags map[string]string) *schema.Set {
+	set := schema.NewSet(TagsHash, nil)
+	for k, v := range tags {
+		set.Add(map[string]interface{}{
+			"key":   k,
+			"value": v,
+		})
+	}
+	return set
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTaggedResource tags of api are kept in remote
+func mockTaggedResource(remote map[string]string) *schema.Resource {
+	read := func(d *schema.ResourceData, meta interface{}) error {
+		return d.Set("tags", mapToTags(remote))
+	}
+	return &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			for k, v := range tagsToMap(d.Get("tags")) {
+				remote[k] = v
+			}
+			d.SetId("vpc-1")
+			return read(d, meta)
+		},
+		Read: read,
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			add, remove := GetTagsDifference(d)
+			for k := range tagsToMap(remove) {
+				delete(remote, k)
+			}
+			for k, v := range tagsToMap(add) {
+				remote[k] = v
+			}
+			return read(d, meta)
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"tags": TagsSchema(),
+		},
+	}
+}
+
+func Test_ResourceDefaultTags(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	config := server.Config()
+	config.DefaultTags = map[string]string{"owner": "sre", "env": "prod"}
+	config.IgnoreTags = IgnoreTags{Keys: []string{"managed-by"}, KeyPrefixes: []string{"ops:"}}
+	client, err := config.Client()
+	assert.Nil(t, err)
+
+	remote := make(map[string]string)
+	r := ResourceDefaultTags(mockTaggedResource(remote))
+	assert.NotNil(t, r.Schema[TagsAllField])
+	assert.True(t, r.Schema[TagsAllField].Computed)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+			map[string]interface{}{"key": "app", "value": "web"},
+		},
+	})
+	assert.Nil(t, r.Create(d, client))
+	assert.Equal(t, map[string]string{"owner": "sre", "env": "dev", "app": "web"}, remote)
+	assert.Equal(t, map[string]string{"env": "dev", "app": "web"}, tagsToMap(d.Get("tags")))
+	assert.Equal(t, map[string]string{"owner": "sre", "env": "dev", "app": "web"}, tagsToMap(d.Get("tags_all")))
+
+	// tags added by other tooling are ignored
+	remote["managed-by"] = "cmdb"
+	remote["ops:team"] = "infra"
+	assert.Nil(t, r.Read(d, client))
+	assert.Equal(t, map[string]string{"env": "dev", "app": "web"}, tagsToMap(d.Get("tags")))
+	assert.Equal(t, map[string]string{"owner": "sre", "env": "dev", "app": "web"}, tagsToMap(d.Get("tags_all")))
+
+	// only default tags changed
+	config.DefaultTags = map[string]string{"owner": "platform"}
+	client, err = config.Client()
+	assert.Nil(t, err)
+	state := d.State()
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+			map[string]interface{}{"key": "app", "value": "web"},
+		},
+	}), client)
+	assert.Nil(t, err)
+	assert.NotNil(t, diff)
+	assert.False(t, diff.RequiresNew())
+
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Update(d, client))
+	assert.Equal(t, map[string]string{"owner": "platform", "env": "dev", "app": "web", "managed-by": "cmdb", "ops:team": "infra"}, remote)
+	assert.Equal(t, map[string]string{"env": "dev", "app": "web"}, tagsToMap(d.Get("tags")))
+
+	// nothing changed
+	diff, err = r.Diff(d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+			map[string]interface{}{"key": "app", "value": "web"},
+		},
+	}), client)
+	assert.Nil(t, err)
+	assert.Nil(t, diff)
+}
+
+func Test_TagsEnabled(t *testing.T) {
+	assert.True(t, TagsEnabled(&schema.Resource{Schema: map[string]*schema.Schema{"tags": TagsSchema()}}))
+	assert.False(t, TagsEnabled(&schema.Resource{Schema: map[string]*schema.Schema{"tags": TagsSchemaComputed()}}))
+	assert.False(t, TagsEnabled(&schema.Resource{Schema: map[string]*schema.Schema{}}))
+}
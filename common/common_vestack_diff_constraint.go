@@ -0,0 +1,173 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DiffConstraint a cross-field constraint of a resource checked at plan time.
+// On update the constraint is only checked when one of its fields changes, so the existing resources are never blocked
+type DiffConstraint struct {
+	Fields []string
+	Check  func(diff *schema.ResourceDiff) error
+}
+
+// DiffConstraintEnabled the services declare the constraints of their resources, enforced by ResourceDiffConstraints
+type DiffConstraintEnabled interface {
+	DiffConstraints() []DiffConstraint
+}
+
+// ResourceDiffConstraints check the constraints of service before the CustomizeDiff of resource
+func ResourceDiffConstraints(resource *schema.Resource, service DiffConstraintEnabled) *schema.Resource {
+	constraints := service.DiffConstraints()
+	customizeDiff := resource.CustomizeDiff
+	resource.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+		if err := CheckDiffConstraints(diff, constraints); err != nil {
+			return err
+		}
+		if customizeDiff != nil {
+			return customizeDiff(diff, meta)
+		}
+		return nil
+	}
+	return resource
+}
+
+// CheckDiffConstraints all violated constraints are returned in one error
+func CheckDiffConstraints(diff *schema.ResourceDiff, constraints []DiffConstraint) error {
+	var errs []string
+	for _, constraint := range constraints {
+		if diff.Id() != "" && !diffHasChange(diff, constraint.Fields) {
+			continue
+		}
+		if err := constraint.Check(diff); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func diffHasChange(diff *schema.ResourceDiff, fields []string) bool {
+	for _, field := range fields {
+		if diff.HasChange(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffCondition the constraints take effect when the value of Field is one of Values
+type DiffCondition struct {
+	Field  string
+	Values []interface{}
+}
+
+// When e.g. When("instance_charge_type", "PrePaid").Required("period")
+func When(field string, values ...interface{}) DiffCondition {
+	return DiffCondition{
+		Field:  field,
+		Values: values,
+	}
+}
+
+// match the unknown value never matches
+func (c DiffCondition) match(diff *schema.ResourceDiff) bool {
+	if !diff.NewValueKnown(c.Field) {
+		return false
+	}
+	v := fmt.Sprint(diff.Get(c.Field))
+	for _, value := range c.Values {
+		if fmt.Sprint(value) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (c DiffCondition) String() string {
+	var values []string
+	for _, v := range c.Values {
+		values = append(values, fmt.Sprintf("%v", v))
+	}
+	return fmt.Sprintf("%s is %s", c.Field, strings.Join(values, " or "))
+}
+
+// Required the fields must be set, the unknown values are treated as set
+func (c DiffCondition) Required(fields ...string) DiffConstraint {
+	return DiffConstraint{
+		Fields: append([]string{c.Field}, fields...),
+		Check: func(diff *schema.ResourceDiff) error {
+			if !c.match(diff) {
+				return nil
+			}
+			for _, field := range fields {
+				if _, ok := diff.GetOk(field); !ok && diff.NewValueKnown(field) {
+					return fmt.Errorf("%q is required when %s", field, c)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Conflicts the fields must not be set, the unknown values are treated as not set
+func (c DiffCondition) Conflicts(fields ...string) DiffConstraint {
+	return DiffConstraint{
+		Fields: append([]string{c.Field}, fields...),
+		Check: func(diff *schema.ResourceDiff) error {
+			if !c.match(diff) {
+				return nil
+			}
+			for _, field := range fields {
+				if _, ok := diff.GetOk(field); ok && diff.NewValueKnown(field) {
+					return fmt.Errorf("%q can not be set when %s", field, c)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// IntBetween the int fields must be in [min, max], the unknown values are skipped
+func (c DiffCondition) IntBetween(min, max int, fields ...string) DiffConstraint {
+	return DiffConstraint{
+		Fields: append([]string{c.Field}, fields...),
+		Check: func(diff *schema.ResourceDiff) error {
+			if !c.match(diff) {
+				return nil
+			}
+			for _, field := range fields {
+				if !diff.NewValueKnown(field) {
+					continue
+				}
+				if v, ok := diff.Get(field).(int); ok && (v < min || v > max) {
+					return fmt.Errorf("%q must be in [%d, %d] when %s, got %d", field, min, max, c, v)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// IntNotGreater the int field low must not be greater than high, e.g. the start and end of a port range
+func IntNotGreater(low, high string) DiffConstraint {
+	return DiffConstraint{
+		Fields: []string{low, high},
+		Check: func(diff *schema.ResourceDiff) error {
+			if !diff.NewValueKnown(low) || !diff.NewValueKnown(high) {
+				return nil
+			}
+			l, _ := diff.Get(low).(int)
+			h, _ := diff.Get(high).(int)
+			if l > h {
+				return fmt.Errorf("%q %d must not be greater than %q %d", low, l, high, h)
+			}
+			return nil
+		},
+	}
+}
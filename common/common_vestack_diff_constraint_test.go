@@ -0,0 +1,126 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockConstraintService struct{}
+
+func (s *mockConstraintService) DiffConstraints() []DiffConstraint {
+	return []DiffConstraint{
+		When("keep_image_credential", true).Conflicts("password", "key_pair_name"),
+		When("charge_type", "PrePaid").Required("period"),
+		When("protocol", "tcp", "udp").IntBetween(1, 65535, "port_start", "port_end"),
+		IntNotGreater("port_start", "port_end"),
+	}
+}
+
+func mockConstraintResource() *schema.Resource {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"keep_image_credential": {Type: schema.TypeBool, Optional: true},
+			"password":              {Type: schema.TypeString, Optional: true},
+			"key_pair_name":         {Type: schema.TypeString, Optional: true, Computed: true},
+			"charge_type":           {Type: schema.TypeString, Optional: true},
+			"period":                {Type: schema.TypeInt, Optional: true},
+			"protocol":              {Type: schema.TypeString, Optional: true},
+			"port_start":            {Type: schema.TypeInt, Optional: true},
+			"port_end":              {Type: schema.TypeInt, Optional: true},
+			"name":                  {Type: schema.TypeString, Optional: true},
+		},
+	}
+	return ResourceDiffConstraints(r, &mockConstraintService{})
+}
+
+func Test_ResourceDiffConstraints(t *testing.T) {
+	cases := []struct {
+		name   string
+		state  map[string]string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "valid",
+			config: map[string]interface{}{"keep_image_credential": true, "charge_type": "PrePaid", "period": 1, "protocol": "tcp", "port_start": 80, "port_end": 80},
+		},
+		{
+			name:   "conflicts",
+			config: map[string]interface{}{"keep_image_credential": true, "password": "p"},
+			err:    `"password" can not be set when keep_image_credential is true`,
+		},
+		{
+			name:   "required",
+			config: map[string]interface{}{"charge_type": "PrePaid"},
+			err:    `"period" is required when charge_type is PrePaid`,
+		},
+		{
+			name:   "required unknown",
+			config: map[string]interface{}{"charge_type": "PrePaid", "period": "74D93920-ED26-11E3-AC10-0800200C9A66"},
+		},
+		{
+			name:   "condition unknown",
+			config: map[string]interface{}{"charge_type": "74D93920-ED26-11E3-AC10-0800200C9A66"},
+		},
+		{
+			name:   "range and order",
+			config: map[string]interface{}{"protocol": "udp", "port_start": 90, "port_end": 80, "keep_image_credential": true, "password": "p"},
+			err:    `"password" can not be set when keep_image_credential is true; "port_start" 90 must not be greater than "port_end" 80`,
+		},
+		{
+			name:   "range",
+			config: map[string]interface{}{"protocol": "udp", "port_start": -1, "port_end": -1},
+			err:    `"port_start" must be in [1, 65535] when protocol is tcp or udp, got -1`,
+		},
+		{
+			name:   "update unchanged fields",
+			state:  map[string]string{"id": "i-1", "keep_image_credential": "true", "key_pair_name": "kp", "name": "a"},
+			config: map[string]interface{}{"keep_image_credential": true, "name": "b"},
+		},
+		{
+			name:   "update changed fields",
+			state:  map[string]string{"id": "i-1", "keep_image_credential": "true", "key_pair_name": "kp"},
+			config: map[string]interface{}{"keep_image_credential": true, "password": "p"},
+			err:    `"password" can not be set when keep_image_credential is true`,
+		},
+	}
+	r := mockConstraintResource()
+	for _, c := range cases {
+		var state *terraform.InstanceState
+		if c.state != nil {
+			state = &terraform.InstanceState{ID: c.state["id"], Attributes: c.state}
+		}
+		_, err := r.Diff(state, terraform.NewResourceConfigRaw(c.config), nil)
+		if c.err == "" {
+			assert.NoError(t, err, c.name)
+		} else if assert.Error(t, err, c.name) {
+			assert.Contains(t, err.Error(), c.err, c.name)
+		}
+	}
+}
+
+func Test_ResourceDiffConstraints_Chain(t *testing.T) {
+	called := false
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"charge_type": {Type: schema.TypeString, Optional: true},
+			"period":      {Type: schema.TypeInt, Optional: true},
+		},
+		CustomizeDiff: func(diff *schema.ResourceDiff, meta interface{}) error {
+			called = true
+			return nil
+		},
+	}
+	r = ResourceDiffConstraints(r, &mockConstraintService{})
+
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{"charge_type": "PrePaid"}), nil)
+	assert.Error(t, err)
+	assert.False(t, called)
+
+	_, err = r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{"charge_type": "PrePaid", "period": 12}), nil)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -3,14 +3,14 @@ package common
 import (
 	"context"
 	"fmt"
-	"time"
 
-	re "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/time/rate"
 )
 
+// Dispatcher the locks, semaphores and rates of a call are taken from the SdkClient of the resource service,
+// so every provider instance and region has its own state even when they share a dispatcher
 type Dispatcher struct {
 	rateInfo *RateInfo
 }
@@ -35,6 +35,32 @@ type RateInfo struct {
 type Rate struct {
 	Limiter   *rate.Limiter
 	Semaphore *semaphore.Weighted
+
+	// settings of NewRate, every client gets its own copy of the rate
+	scoped      bool
+	qps         float64
+	burst       int
+	concurrency int64
+}
+
+// NewRate qps <= 0 or concurrency <= 0 means no limit, the rate is copied for every provider instance and region
+func NewRate(qps float64, burst int, concurrency int64) *Rate {
+	r := &Rate{
+		scoped:      true,
+		qps:         qps,
+		burst:       burst,
+		concurrency: concurrency,
+	}
+	if qps > 0 {
+		if burst <= 0 {
+			burst = 1
+		}
+		r.Limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+	if concurrency > 0 {
+		r.Semaphore = semaphore.NewWeighted(concurrency)
+	}
+	return r
 }
 
 func NewRateLimitDispatcher(r *RateInfo) *Dispatcher {
@@ -43,30 +69,45 @@ func NewRateLimitDispatcher(r *RateInfo) *Dispatcher {
 	}
 }
 
-func (d *Dispatcher) Create(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
-	defer func() {
-		if d.rateInfo != nil && d.rateInfo.Create != nil && d.rateInfo.Create.Semaphore != nil {
-			d.rateInfo.Create.Semaphore.Release(1)
-		}
-	}()
-	if d.rateInfo != nil && d.rateInfo.Create != nil {
-		ctx := context.Background()
-		if d.rateInfo.Create.Limiter != nil {
-			err = d.rateInfo.Create.Limiter.Wait(ctx)
-			if err != nil {
-				return err
-			}
-		}
-		if d.rateInfo.Create.Semaphore != nil {
-			err = d.rateInfo.Create.Semaphore.Acquire(ctx, 1)
-			if err != nil {
-				return err
-			}
-		}
+// wait the rate configured in provider for the service and operation, then the rate of dispatcher selected by fn,
+// release must be called when err is nil
+func (d *Dispatcher) wait(ctx context.Context, resourceService ResourceService, operation string, fn func(r *RateInfo) *Rate) (release func(), err error) {
+	providerRelease, err := waitRate(ctx, resourceService.GetClient().rate(RateLimitServiceName(resourceService), operation))
+	if err != nil {
+		return nil, err
+	}
+	if d.rateInfo == nil {
+		return providerRelease, nil
+	}
+	dispatcherRelease, err := waitRate(ctx, resourceService.GetClient().scopedRate(fn(d.rateInfo)))
+	if err != nil {
+		providerRelease()
+		return nil, err
+	}
+	return func() {
+		dispatcherRelease()
+		providerRelease()
+	}, nil
+}
 
+func (d *Dispatcher) Create(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
+	defer bindResourceLog(resourceService, RateOperationCreate, resourceDate)()
+	defer resourceService.GetClient().invalidateReadCache()
+	resourceService.GetClient().invalidateReadCache()
+	ctx := resourceService.GetClient().StopContext()
+	release, err := d.wait(ctx, resourceService, RateOperationCreate, func(r *RateInfo) *Rate {
+		return r.Create
+	})
+	if err != nil {
+		return err
 	}
+	defer release()
 
 	callbacks := resourceService.CreateResource(resourceDate, resource)
+	if tagEnabled, ok := resourceService.(TagEnabled); ok && !tagEnabled.TagInfo().CreateWithTags {
+		callbacks = append(callbacks, NewTagService(resourceService.GetClient()).SetTags(tagEnabled.TagInfo(),
+			resourceDate, resourceService)...)
+	}
 	var calls []SdkCall
 	for _, callback := range callbacks {
 		if callback.Err != nil {
@@ -78,30 +119,21 @@ func (d *Dispatcher) Create(resourceService ResourceService, resourceDate *schem
 		}
 		calls = append(calls, callback.Call)
 	}
-	return CallProcess(calls, resourceDate, resourceService.GetClient(), resourceService)
+	return CallProcessWithContext(ctx, calls, resourceDate, resourceService.GetClient(), resourceService)
 }
 
 func (d *Dispatcher) Update(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
-	defer func() {
-		if d.rateInfo != nil && d.rateInfo.Update != nil && d.rateInfo.Update.Semaphore != nil {
-			d.rateInfo.Update.Semaphore.Release(1)
-		}
-	}()
-	if d.rateInfo != nil && d.rateInfo.Update != nil {
-		ctx := context.Background()
-		if d.rateInfo.Update.Limiter != nil {
-			err = d.rateInfo.Update.Limiter.Wait(ctx)
-			if err != nil {
-				return err
-			}
-		}
-		if d.rateInfo.Update.Semaphore != nil {
-			err = d.rateInfo.Update.Semaphore.Acquire(ctx, 1)
-			if err != nil {
-				return err
-			}
-		}
+	defer bindResourceLog(resourceService, RateOperationUpdate, resourceDate)()
+	defer resourceService.GetClient().invalidateReadCache()
+	resourceService.GetClient().invalidateReadCache()
+	ctx := resourceService.GetClient().StopContext()
+	release, err := d.wait(ctx, resourceService, RateOperationUpdate, func(r *RateInfo) *Rate {
+		return r.Update
+	})
+	if err != nil {
+		return err
 	}
+	defer release()
 	var callbacks []Callback
 	if projectUpdateEnabled, ok := resourceService.(ProjectUpdateEnabled); ok {
 		projectUpdateCallback := NewProjectService(resourceService.GetClient()).ModifyProject(projectUpdateEnabled.ProjectTrn(),
@@ -109,6 +141,10 @@ func (d *Dispatcher) Update(resourceService ResourceService, resourceDate *schem
 		callbacks = append(callbacks, projectUpdateCallback...)
 	}
 	callbacks = append(callbacks, resourceService.ModifyResource(resourceDate, resource)...)
+	if tagEnabled, ok := resourceService.(TagEnabled); ok {
+		callbacks = append(callbacks, NewTagService(resourceService.GetClient()).SetTags(tagEnabled.TagInfo(),
+			resourceDate, resourceService)...)
+	}
 
 	var calls []SdkCall
 	for _, callback := range callbacks {
@@ -121,48 +157,33 @@ func (d *Dispatcher) Update(resourceService ResourceService, resourceDate *schem
 		}
 		calls = append(calls, callback.Call)
 	}
-	return CallProcess(calls, resourceDate, resourceService.GetClient(), resourceService)
+	return CallProcessWithContext(ctx, calls, resourceDate, resourceService.GetClient(), resourceService)
 }
 
 func (d *Dispatcher) Read(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
-	defer func() {
-		if d.rateInfo != nil && d.rateInfo.Read != nil && d.rateInfo.Read.Semaphore != nil {
-			d.rateInfo.Read.Semaphore.Release(1)
-		}
-	}()
-	if d.rateInfo != nil && d.rateInfo.Read != nil {
-		ctx := context.Background()
-		if d.rateInfo.Read.Limiter != nil {
-			err = d.rateInfo.Read.Limiter.Wait(ctx)
-			if err != nil {
-				return err
-			}
-		}
-		if d.rateInfo.Read.Semaphore != nil {
-			err = d.rateInfo.Read.Semaphore.Acquire(ctx, 1)
-			if err != nil {
-				return err
-			}
-		}
+	defer bindResourceLog(resourceService, RateOperationRead, resourceDate)()
+	defer resourceService.GetClient().bindReadCache()()
+	ctx := resourceService.GetClient().StopContext()
+	release, err := d.wait(ctx, resourceService, RateOperationRead, func(r *RateInfo) *Rate {
+		return r.Read
+	})
+	if err != nil {
+		return err
 	}
+	defer release()
 
 	var (
 		instance map[string]interface{}
 		callErr  error
 	)
 
-	err = re.Retry(3*time.Minute, func() *re.RetryError {
+	err = resourceService.GetClient().RetryPolicy().Retry(ctx, "Read", func() error {
 		instance, callErr = resourceService.ReadResource(resourceDate, resourceDate.Id())
-		if callErr != nil {
-			if ResourceFlowLimitExceededError(callErr) {
-				return re.RetryableError(callErr)
-			} else {
-				return re.NonRetryableError(fmt.Errorf("error on  reading  resource %q, %w", resourceDate.Id(), callErr))
-			}
-		} else {
-			return nil
-		}
+		return callErr
 	})
+	if err != nil {
+		err = fmt.Errorf("error on  reading  resource %q, %w", resourceDate.Id(), err)
+	}
 
 	if err != nil {
 		return err
@@ -192,26 +213,17 @@ func (d *Dispatcher) Read(resourceService ResourceService, resourceDate *schema.
 }
 
 func (d *Dispatcher) Delete(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
-	defer func() {
-		if d.rateInfo != nil && d.rateInfo.Delete != nil && d.rateInfo.Delete.Semaphore != nil {
-			d.rateInfo.Delete.Semaphore.Release(1)
-		}
-	}()
-	if d.rateInfo != nil && d.rateInfo.Delete != nil {
-		ctx := context.Background()
-		if d.rateInfo.Delete.Limiter != nil {
-			err = d.rateInfo.Delete.Limiter.Wait(ctx)
-			if err != nil {
-				return err
-			}
-		}
-		if d.rateInfo.Delete.Semaphore != nil {
-			err = d.rateInfo.Delete.Semaphore.Acquire(ctx, 1)
-			if err != nil {
-				return err
-			}
-		}
+	defer bindResourceLog(resourceService, RateOperationDelete, resourceDate)()
+	defer resourceService.GetClient().invalidateReadCache()
+	resourceService.GetClient().invalidateReadCache()
+	ctx := resourceService.GetClient().StopContext()
+	release, err := d.wait(ctx, resourceService, RateOperationDelete, func(r *RateInfo) *Rate {
+		return r.Delete
+	})
+	if err != nil {
+		return err
 	}
+	defer release()
 	var (
 		callbacks       []Callback
 		unsubscribeInfo *UnsubscribeInfo
@@ -243,7 +255,7 @@ func (d *Dispatcher) Delete(resourceService ResourceService, resourceDate *schem
 		}
 		calls = append(calls, callback.Call)
 	}
-	return CallProcess(calls, resourceDate, resourceService.GetClient(), resourceService)
+	return CallProcessWithContext(ctx, calls, resourceDate, resourceService.GetClient(), resourceService)
 }
 
 func (d *Dispatcher) Data(resourceService ResourceService, resourceDate *schema.ResourceData, resource *schema.Resource) (err error) {
@@ -252,26 +264,17 @@ func (d *Dispatcher) Data(resourceService ResourceService, resourceDate *schema.
 		condition  map[string]interface{}
 		collection []interface{}
 	)
-	defer func() {
-		if d.rateInfo != nil && d.rateInfo.Data != nil && d.rateInfo.Data.Semaphore != nil {
-			d.rateInfo.Data.Semaphore.Release(1)
-		}
-	}()
-	if d.rateInfo != nil && d.rateInfo.Data != nil {
-		ctx := context.Background()
-		if d.rateInfo.Data.Limiter != nil {
-			err = d.rateInfo.Data.Limiter.Wait(ctx)
-			if err != nil {
-				return err
-			}
-		}
-		if d.rateInfo.Data.Semaphore != nil {
-			err = d.rateInfo.Data.Semaphore.Acquire(ctx, 1)
-			if err != nil {
-				return err
-			}
-		}
+	defer bindResourceLog(resourceService, RateOperationData, resourceDate)()
+	defer resourceService.GetClient().bindReadCache()()
+	ctx := resourceService.GetClient().StopContext()
+	release, err := d.wait(ctx, resourceService, RateOperationData, func(r *RateInfo) *Rate {
+		return r.Data
+	})
+	if err != nil {
+		return err
 	}
+	defer release()
+	resource = WrapDataSource(resource)
 	info = resourceService.DatasourceResources(resourceDate, resource)
 	condition, err = DataSourceToRequest(resourceDate, resource, info)
 	if err != nil {
@@ -292,7 +295,7 @@ func (d *Dispatcher) Data(resourceService ResourceService, resourceDate *schema.
 	default:
 		break
 	}
-	collection, err = resourceService.ReadResources(condition)
+	collection, err = CachedReadResources(resourceService, condition)
 	if err != nil {
 		return err
 	}
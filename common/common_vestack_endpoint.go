@@ -0,0 +1,110 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volcengine/volcengine-go-sdk/volcengine"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/session"
+)
+
+// ServiceOverride provider service_overrides block of one service
+type ServiceOverride struct {
+	// Endpoint host with optional port and scheme, e.g. ecs.example.com:8443 or https://ecs.example.com
+	Endpoint string
+	// Scheme http or https, default is decided by disable_ssl
+	Scheme        string
+	SigningRegion string
+	// Timeout of http request, 0 means use the provider http client
+	Timeout time.Duration
+	// MaxRetries of sdk retryer in addition to RetryPolicy, nil means no sdk retry
+	MaxRetries *int
+}
+
+// ServiceOverrides keyed by lower case service name, e.g. ecs, storage_ebs, tos
+type ServiceOverrides map[string]ServiceOverride
+
+// NewServiceOverrides customer_endpoints only override the endpoint host,
+// they are merged into overrides which do not set the endpoint
+func NewServiceOverrides(endpoints map[string]string, overrides map[string]ServiceOverride) (ServiceOverrides, error) {
+	result := make(ServiceOverrides)
+	for k, v := range endpoints {
+		result[strings.ToLower(k)] = ServiceOverride{Endpoint: v}
+	}
+	for k, v := range overrides {
+		if v.Scheme != "" && v.Scheme != "http" && v.Scheme != "https" {
+			return nil, fmt.Errorf("scheme of service %s must be http or https, got %s", k, v.Scheme)
+		}
+		if v.Endpoint == "" {
+			v.Endpoint = result[strings.ToLower(k)].Endpoint
+		}
+		result[strings.ToLower(k)] = v
+	}
+	return result, nil
+}
+
+func (s ServiceOverrides) get(svc string) (ServiceOverride, bool) {
+	o, ok := s[strings.ToLower(svc)]
+	return o, ok
+}
+
+// host of Endpoint without scheme
+func (o ServiceOverride) host() string {
+	if index := strings.Index(o.Endpoint, "://"); index >= 0 {
+		return strings.TrimSuffix(o.Endpoint[index+3:], "/")
+	}
+	return strings.TrimSuffix(o.Endpoint, "/")
+}
+
+// scheme Scheme first, then the scheme of Endpoint
+func (o ServiceOverride) scheme() string {
+	if o.Scheme != "" {
+		return o.Scheme
+	}
+	if index := strings.Index(o.Endpoint, "://"); index >= 0 {
+		return strings.ToLower(o.Endpoint[:index])
+	}
+	return ""
+}
+
+// configs the config of service merged into session config,
+// pass them to session.ClientConfig or New of typed sdk clients
+func (s ServiceOverrides) configs(sess *session.Session, svc string) []*volcengine.Config {
+	o, ok := s.get(svc)
+	if !ok {
+		return nil
+	}
+	cfg := volcengine.NewConfig()
+	if o.Endpoint != "" {
+		cfg.WithEndpoint(o.host())
+	}
+	if scheme := o.scheme(); scheme != "" {
+		cfg.WithDisableSSL(scheme == "http")
+	}
+	if o.SigningRegion != "" {
+		cfg.WithRegion(o.SigningRegion)
+	}
+	if o.MaxRetries != nil {
+		cfg.WithMaxRetries(*o.MaxRetries)
+	}
+	if o.Timeout > 0 {
+		base := sess.Config.HTTPClient
+		if base == nil {
+			base = http.DefaultClient
+		}
+		httpClient := *base
+		httpClient.Timeout = o.Timeout
+		cfg.WithHTTPClient(&httpClient)
+	}
+	return []*volcengine.Config{cfg}
+}
+
+// bypassHost the overridden endpoint host of bypass service, or defaultHost
+func (s ServiceOverrides) bypassHost(svc string, defaultHost string) string {
+	if o, ok := s.get(svc); ok && o.Endpoint != "" {
+		return o.host()
+	}
+	return defaultHost
+}
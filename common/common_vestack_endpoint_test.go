@@ -0,0 +1,68 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ServiceOverrides(t *testing.T) {
+	overrides, err := NewServiceOverrides(map[string]string{
+		"vpc": "vpc.example.com:8443",
+		"ecs": "ecs.example.com",
+	}, map[string]ServiceOverride{
+		"ECS": {Scheme: "http", SigningRegion: "cn-mock-2"},
+		"tos": {Endpoint: "https://tos.example.com:9443/"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc.example.com:8443", overrides["vpc"].host())
+	assert.Equal(t, "ecs.example.com", overrides["ecs"].host())
+	assert.Equal(t, "http", overrides["ecs"].scheme())
+	assert.Equal(t, "tos.example.com:9443", overrides.bypassHost("tos", "tos-cn-mock.volces.com"))
+	assert.Equal(t, "https", overrides["tos"].scheme())
+	assert.Equal(t, "tls-cn-mock.volces.com", overrides.bypassHost("TLS", "tls-cn-mock.volces.com"))
+
+	_, err = NewServiceOverrides(nil, map[string]ServiceOverride{"ecs": {Scheme: "ftp"}})
+	assert.NotNil(t, err)
+}
+
+func Test_ServiceOverrides_Client(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+	server.On("DescribeInstances", MockResponse{Result: map[string]interface{}{"Instances": []interface{}{}}})
+	server.OnFunc("DescribeSubnets", func(req *MockRequest) *MockResponse {
+		time.Sleep(200 * time.Millisecond)
+		return &MockResponse{Result: map[string]interface{}{}}
+	})
+
+	config := server.Config()
+	config.CustomerEndpoints = map[string]string{
+		"vpc": "vpc.example.com:8443",
+	}
+	maxRetries := 0
+	config.ServiceOverrides = map[string]ServiceOverride{
+		"ecs": {Endpoint: "ecs.example.com:8080", SigningRegion: "cn-mock-2"},
+		"vpc": {Timeout: 50 * time.Millisecond, MaxRetries: &maxRetries},
+	}
+	client, err := config.Client()
+	assert.Nil(t, err)
+
+	// universal client
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc.example.com:8443", server.Requests("DescribeVpcs")[0].Host)
+
+	// typed sdk client
+	_, err = client.EcsClient.DescribeInstancesCommon(&map[string]interface{}{})
+	assert.Nil(t, err)
+	req := server.Requests("DescribeInstances")[0]
+	assert.Equal(t, "ecs.example.com:8080", req.Host)
+	assert.Contains(t, req.Header.Get("Authorization"), "/cn-mock-2/ecs/")
+
+	// timeout of vpc
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeSubnets"), nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(server.Requests("DescribeSubnets")))
+}
@@ -2,26 +2,27 @@ package common
 
 import (
 	"strings"
-
-	"github.com/volcengine/volcengine-go-sdk/volcengine/volcengineerr"
 )
 
 func ResourceNotFoundError(err error) bool {
-	if e, ok := err.(volcengineerr.RequestFailure); ok && e.StatusCode() == 404 {
-		return true
+	if err == nil {
+		return false
 	}
-	errMessage := strings.ToLower(err.Error())
+	return ClassifyError(err) == ErrorCategoryNotFound || isNotFoundMessage(strings.ToLower(err.Error()))
+}
+
+// isNotFoundMessage errors formatted by services lost the code, e.g. "vpc xxx not exist"
+func isNotFoundMessage(errMessage string) bool {
 	return strings.Contains(errMessage, "notfound") ||
 		strings.Contains(errMessage, "not found") ||
 		strings.Contains(errMessage, "not exist") ||
 		strings.Contains(errMessage, "not associate") ||
-		strings.Contains(errMessage, "invalid") ||
 		strings.Contains(errMessage, "not_found") ||
 		strings.Contains(errMessage, "notexist")
 }
 
 func ResourceFlowLimitExceededError(err error) bool {
-	return strings.Contains(err.Error(), "FlowLimitExceeded")
+	return ClassifyError(err) == ErrorCategoryThrottled
 }
 
 func UnsubscribeProductError(err error) bool {
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HttpTransport provider http_client block, every provider instance has its own http client
+type HttpTransport struct {
+	// NoProxy comma separated hosts bypass ProxyUrl, default is NO_PROXY env
+	NoProxy string
+	// CaFile pem bundle trusted besides the system roots
+	CaFile string
+	// ClientCertFile and ClientKeyFile pem files of mTLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// TlsMinVersion 1.0, 1.1, 1.2 or 1.3
+	TlsMinVersion       string
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	// Timeout of a http request, 0 means no timeout
+	Timeout time.Duration
+}
+
+// HttpTransportFromEnv the transport of provider without http_client block, from VESTACK_NO_PROXY and VESTACK_CA_FILE env
+func HttpTransportFromEnv() HttpTransport {
+	return HttpTransport{
+		NoProxy: os.Getenv("VESTACK_NO_PROXY"),
+		CaFile:  os.Getenv("VESTACK_CA_FILE"),
+	}
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewHttpClient build a dedicated http client, the proxy is used for every host except NoProxy,
+// proxy env (HTTPS_PROXY/NO_PROXY) is used when proxyUrl is empty
+func NewHttpClient(proxyUrl string, t HttpTransport) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+	if t.TlsMinVersion != "" {
+		version, ok := tlsVersions[t.TlsMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("tls min version must be one of 1.0, 1.1, 1.2, 1.3, got %s", t.TlsMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+	if t.CaFile != "" {
+		bs, err := ioutil.ReadFile(t.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file %s error: %w", t.CaFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("no certificate found in ca file %s", t.CaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if t.ClientCertFile != "" || t.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.ClientCertFile, t.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate error: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if proxyUrl != "" {
+		if _, err := url.Parse(proxyUrl); err != nil {
+			return nil, fmt.Errorf("invalid proxy url %s: %w", proxyUrl, err)
+		}
+		noProxy := t.NoProxy
+		if noProxy == "" {
+			noProxy = os.Getenv("NO_PROXY")
+			if noProxy == "" {
+				noProxy = os.Getenv("no_proxy")
+			}
+		}
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxyUrl,
+			HTTPSProxy: proxyUrl,
+			NoProxy:    noProxy,
+		}).ProxyFunc()
+		proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		MaxConnsPerHost:       t.MaxConnsPerHost,
+	}
+	if t.MaxIdleConns > 0 {
+		transport.MaxIdleConns = t.MaxIdleConns
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   t.Timeout,
+	}, nil
+}
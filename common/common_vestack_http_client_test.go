@@ -0,0 +1,92 @@
+package common
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewHttpClient_CaFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "http_client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.Nil(t, ioutil.WriteFile(caFile, ca, 0600))
+
+	client, err := NewHttpClient("", HttpTransport{})
+	assert.Nil(t, err)
+	_, err = client.Get(server.URL)
+	assert.NotNil(t, err)
+
+	client, err = NewHttpClient("", HttpTransport{CaFile: caFile, TlsMinVersion: "1.2"})
+	assert.Nil(t, err)
+	resp, err := client.Get(server.URL)
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, uint16(tls.VersionTLS12), client.Transport.(*http.Transport).TLSClientConfig.MinVersion)
+
+	// the env of provider without http_client block
+	defer os.Unsetenv("VESTACK_CA_FILE")
+	assert.Nil(t, os.Setenv("VESTACK_CA_FILE", caFile))
+	assert.Equal(t, caFile, HttpTransportFromEnv().CaFile)
+	client, err = NewHttpClient("", HttpTransportFromEnv())
+	assert.Nil(t, err)
+	resp, err = client.Get(server.URL)
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	_, err = NewHttpClient("", HttpTransport{TlsMinVersion: "1.4"})
+	assert.NotNil(t, err)
+	_, err = NewHttpClient("", HttpTransport{CaFile: filepath.Join(dir, "missing.pem")})
+	assert.NotNil(t, err)
+	_, err = NewHttpClient("", HttpTransport{ClientCertFile: caFile})
+	assert.NotNil(t, err)
+}
+
+func Test_NewHttpClient_NoProxy(t *testing.T) {
+	client, err := NewHttpClient("http://proxy.example.com:3128", HttpTransport{NoProxy: ".internal.example.com,10.0.0.0/8"})
+	assert.Nil(t, err)
+	proxy := client.Transport.(*http.Transport).Proxy
+
+	for target, expected := range map[string]string{
+		"https://ecs.cn-beijing.volcengineapi.com": "http://proxy.example.com:3128",
+		"https://ecs.internal.example.com":         "",
+		"https://10.1.2.3":                         "",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		u, err := proxy(req)
+		assert.Nil(t, err)
+		if expected == "" {
+			assert.Nil(t, u, target)
+		} else {
+			assert.Equal(t, expected, u.String(), target)
+		}
+	}
+
+	defer os.Unsetenv("VESTACK_NO_PROXY")
+	assert.Nil(t, os.Setenv("VESTACK_NO_PROXY", ".internal.example.com"))
+	client, err = NewHttpClient("http://proxy.example.com:3128", HttpTransportFromEnv())
+	assert.Nil(t, err)
+	req, _ := http.NewRequest(http.MethodGet, "https://ecs.internal.example.com", nil)
+	u, err := client.Transport.(*http.Transport).Proxy(req)
+	assert.Nil(t, err)
+	assert.Nil(t, u)
+
+	// the default client is not touched
+	assert.Nil(t, http.DefaultClient.Transport)
+}
@@ -1,24 +1,96 @@
 package common
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
-var locks map[string]*sync.Mutex
-var methodLock *sync.Mutex
+// lockSet every key is a channel with one buffer, lock can be abandoned when ctx is done
+type lockSet struct {
+	methodLock sync.Mutex
+	locks      map[string]chan struct{}
+}
+
+func newLockSet() *lockSet {
+	return &lockSet{
+		locks: make(map[string]chan struct{}),
+	}
+}
+
+func (l *lockSet) get(key string) chan struct{} {
+	l.methodLock.Lock()
+	defer l.methodLock.Unlock()
+	if _, ok := l.locks[key]; !ok {
+		l.locks[key] = make(chan struct{}, 1)
+	}
+	return l.locks[key]
+}
+
+// tryLock return error without holding the lock when ctx is done
+func (l *lockSet) tryLock(ctx context.Context, key string) error {
+	select {
+	case l.get(key) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for lock %s cancelled: %w", key, ctx.Err())
+	}
+}
+
+func (l *lockSet) release(key string) {
+	<-l.get(key)
+}
+
+var (
+	// accountLocks the locks of every account and region, the provider instances (e.g. aliases) of the same
+	// account and region serialize the operations of the same resource
+	accountLocks     = make(map[string]*lockSet)
+	accountLocksLock sync.Mutex
+)
+
+func accountLockSet(account, region string) *lockSet {
+	accountLocksLock.Lock()
+	defer accountLocksLock.Unlock()
+	key := account + "/" + region
+	if _, ok := accountLocks[key]; !ok {
+		accountLocks[key] = newLockSet()
+	}
+	return accountLocks[key]
+}
+
+// locks shared by clients which are not built by Config.Client
+var locks *lockSet
 
 func InitLocks() {
-	locks = make(map[string]*sync.Mutex)
-	methodLock = new(sync.Mutex)
+	locks = newLockSet()
 }
 
 func ReleaseLock(key string) {
-	locks[key].Unlock()
+	locks.release(key)
 }
 
 func TryLock(key string) {
-	methodLock.Lock()
-	if _, ok := locks[key]; !ok {
-		locks[key] = new(sync.Mutex)
+	_ = TryLockContext(context.Background(), key)
+}
+
+// TryLockContext return error without holding the lock when ctx is done
+func TryLockContext(ctx context.Context, key string) error {
+	return locks.tryLock(ctx, key)
+}
+
+// TryLockContext the locks of the account and region of this client, shared by every provider instance and region
+// client which use the credentials of the same account in the region
+func (c *SdkClient) TryLockContext(ctx context.Context, key string) error {
+	return c.lockSet().tryLock(ctx, key)
+}
+
+func (c *SdkClient) ReleaseLock(key string) {
+	c.lockSet().release(key)
+}
+
+func (c *SdkClient) lockSet() *lockSet {
+	if c == nil || c.locks == nil {
+		return locks
 	}
-	methodLock.Unlock()
-	locks[key].Lock()
+	return c.locks
 }
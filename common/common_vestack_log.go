@@ -0,0 +1,69 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/request"
+)
+
+// RegisterSensitiveFields the values of Sensitive fields are redacted in log
+func RegisterSensitiveFields(resources map[string]*schema.Resource) {
+	for _, r := range resources {
+		registerSensitiveSchema(r.Schema)
+	}
+}
+
+func registerSensitiveSchema(m map[string]*schema.Schema) {
+	for k, v := range m {
+		if v.Sensitive {
+			logger.RegisterSensitiveKeys(k)
+		}
+		if elem, ok := v.Elem.(*schema.Resource); ok {
+			registerSensitiveSchema(elem.Schema)
+		}
+	}
+}
+
+// logRequestHandler log every api request with its request id, so the log of a resource can be correlated with server side
+var logRequestHandler = request.NamedHandler{
+	Name: "vestack.LogRequestHandler",
+	Fn: func(r *request.Request) {
+		fields := logger.Fields{
+			"action":     r.Operation.Name,
+			"service":    r.ClientInfo.ServiceName,
+			"request_id": r.RequestID,
+			"duration":   time.Since(r.Time).String(),
+			"retries":    r.RetryCount,
+		}
+		if r.HTTPResponse != nil {
+			fields["status"] = r.HTTPResponse.StatusCode
+		}
+		if r.Error != nil {
+			fields["error"] = r.Error.Error()
+			logger.Log(logger.LevelWarn, fields, "api request failed")
+			return
+		}
+		logger.Log(logger.LevelDebug, fields, "api request completed")
+	},
+}
+
+// bindResourceLog attach the resource and id to every log of current goroutine
+func bindResourceLog(resourceService interface{}, operation string, d *schema.ResourceData) (unbind func()) {
+	var resourceName string
+	t := reflect.TypeOf(resourceService)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil {
+		resourceName = t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	}
+	return logger.Bind(logger.Fields{
+		"resource":    resourceName,
+		"resource_id": d.Id(),
+		"operation":   operation,
+	})
+}
@@ -0,0 +1,319 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// MockServer is an in-process fake OpenAPI endpoint, SdkClient built by MockServer.Client
+// send every Universal, BypassSvc and typed sdk request to it
+type MockServer struct {
+	server   *httptest.Server
+	lock     sync.Mutex
+	scripts  map[string][]MockHandler
+	requests []*MockRequest
+	seq      int
+}
+
+// MockRequest is the decoded request received by MockServer
+type MockRequest struct {
+	Service string
+	Action  string
+	Version string
+	Method  string
+	Host    string
+	Path    string
+	Query   url.Values
+	Header  http.Header
+	Body    []byte
+	// Params merge query and form/json body params
+	Params map[string]interface{}
+}
+
+type MockError struct {
+	Code    string
+	Message string
+}
+
+// MockResponse is a scripted response
+// Result is wrapped with ResponseMetadata, Body is written as is (bypass service)
+type MockResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Result     interface{}
+	Error      *MockError
+	Body       string
+}
+
+type MockHandler func(req *MockRequest) *MockResponse
+
+// MockFixtures Action(or "METHOD /path" for bypass service) -> responses
+type MockFixtures map[string][]MockResponse
+
+func NewMockServer() *MockServer {
+	m := &MockServer{
+		scripts: make(map[string][]MockHandler),
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+func (m *MockServer) Close() {
+	m.server.Close()
+}
+
+// Addr the listen address of mock server, e.g. 127.0.0.1:12345
+func (m *MockServer) Addr() string {
+	return m.server.Listener.Addr().String()
+}
+
+// HTTPClient every request send by this client will be routed to mock server whatever the host is
+func (m *MockServer) HTTPClient() *http.Client {
+	addr := m.Addr()
+	dialer := &net.Dialer{}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
+
+func (m *MockServer) Config() *Config {
+	return &Config{
+		AccessKey:         "mock-access-key",
+		SecretKey:         "mock-secret-key",
+		Region:            "cn-mock",
+		Endpoint:          m.Addr(),
+		DisableSSL:        true,
+		CustomerHeaders:   map[string]string{},
+		CustomerEndpoints: map[string]string{},
+		HttpClient:        m.HTTPClient(),
+	}
+}
+
+func (m *MockServer) Client() (*SdkClient, error) {
+	return m.Config().Client()
+}
+
+// On script the responses of key in order, the last response will be reused when script is exhausted
+func (m *MockServer) On(key string, responses ...MockResponse) *MockServer {
+	var handlers []MockHandler
+	for _, resp := range responses {
+		r := resp
+		handlers = append(handlers, func(req *MockRequest) *MockResponse {
+			return &r
+		})
+	}
+	return m.OnFunc(key, handlers...)
+}
+
+// OnFunc script the handlers of key in order, the last handler will be reused when script is exhausted
+func (m *MockServer) OnFunc(key string, handlers ...MockHandler) *MockServer {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.scripts[key] = append(m.scripts[key], handlers...)
+	return m
+}
+
+// Reset drop all scripts and recorded requests
+func (m *MockServer) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.scripts = make(map[string][]MockHandler)
+	m.requests = nil
+}
+
+func (m *MockServer) LoadFixtures(fixtures MockFixtures) *MockServer {
+	for k, v := range fixtures {
+		m.On(k, v...)
+	}
+	return m
+}
+
+// LoadFixturesFile load fixtures from a json file, e.g. {"DescribeInstances":[{"Result":{...}}]}
+func (m *MockServer) LoadFixturesFile(filePath string) error {
+	var fixtures MockFixtures
+	bs, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(bs, &fixtures); err != nil {
+		return fmt.Errorf("unmarshal fixtures %s error: %w", filePath, err)
+	}
+	m.LoadFixtures(fixtures)
+	return nil
+}
+
+// Requests the received requests of key, all requests when key is empty
+func (m *MockServer) Requests(key string) []*MockRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var result []*MockRequest
+	for _, req := range m.requests {
+		if key == "" || req.key() == key {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+func (r *MockRequest) key() string {
+	if r.Action != "" {
+		return r.Action
+	}
+	return r.Method + " " + r.Path
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := newMockRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.lock.Lock()
+	m.seq++
+	requestId := fmt.Sprintf("mock-request-%d", m.seq)
+	m.requests = append(m.requests, req)
+	var handler MockHandler
+	if handlers, ok := m.scripts[req.key()]; ok && len(handlers) > 0 {
+		handler = handlers[0]
+		if len(handlers) > 1 {
+			m.scripts[req.key()] = handlers[1:]
+		}
+	}
+	m.lock.Unlock()
+
+	var resp *MockResponse
+	if handler != nil {
+		resp = handler(req)
+	}
+	if resp == nil {
+		resp = &MockResponse{
+			StatusCode: http.StatusNotFound,
+			Error: &MockError{
+				Code:    "MockNotFound",
+				Message: fmt.Sprintf("no mock response for %s", req.key()),
+			},
+		}
+	}
+	writeMockResponse(w, req, resp, requestId)
+}
+
+func writeMockResponse(w http.ResponseWriter, req *MockRequest, resp *MockResponse, requestId string) {
+	status := resp.StatusCode
+	for k, v := range resp.Header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("X-Request-Id", requestId)
+
+	if req.Action == "" || resp.Body != "" {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp.Body))
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"RequestId": requestId,
+		"Action":    req.Action,
+		"Version":   req.Version,
+		"Service":   req.Service,
+	}
+	if resp.Error != nil {
+		metadata["Error"] = map[string]interface{}{
+			"Code":    resp.Error.Code,
+			"Message": resp.Error.Message,
+		}
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := map[string]interface{}{
+		"ResponseMetadata": metadata,
+	}
+	if resp.Result != nil {
+		body["Result"] = resp.Result
+	}
+	bs, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(bs)
+}
+
+func newMockRequest(r *http.Request) (*MockRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	req := &MockRequest{
+		Method: r.Method,
+		Host:   r.Host,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+		Params: make(map[string]interface{}),
+	}
+	for k, v := range req.Query {
+		req.Params[k] = v[0]
+	}
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	if len(body) > 0 {
+		if strings.Contains(contentType, "application/json") {
+			var jsonBody map[string]interface{}
+			if json.Unmarshal(body, &jsonBody) == nil {
+				for k, v := range jsonBody {
+					req.Params[k] = v
+				}
+			}
+		} else if form, e := url.ParseQuery(string(body)); e == nil {
+			for k, v := range form {
+				req.Params[k] = v[0]
+			}
+		}
+	}
+	if v, ok := req.Params["Action"].(string); ok {
+		req.Action = v
+	}
+	if v, ok := req.Params["Version"].(string); ok {
+		req.Version = v
+	}
+	req.Service = signedServiceName(r.Header.Get("Authorization"))
+	return req, nil
+}
+
+// signedServiceName parse service from "HMAC-SHA256 Credential=ak/date/region/service/request, ..."
+func signedServiceName(authorization string) string {
+	index := strings.Index(authorization, "Credential=")
+	if index < 0 {
+		return ""
+	}
+	credential := authorization[index+len("Credential="):]
+	if end := strings.Index(credential, ","); end >= 0 {
+		credential = credential[:end]
+	}
+	parts := strings.Split(credential, "/")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,273 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockVpcService struct {
+	Client *SdkClient
+}
+
+func (s *mockVpcService) GetClient() *SdkClient {
+	return s.Client
+}
+
+func (s *mockVpcService) ReadResources(condition map[string]interface{}) ([]interface{}, error) {
+	return WithPageNumberQuery(condition, "PageSize", "PageNumber", 2, 1, func(m map[string]interface{}) ([]interface{}, error) {
+		resp, err := s.Client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &m)
+		if err != nil {
+			return nil, err
+		}
+		results, err := ObtainSdkValue("Result.Vpcs", *resp)
+		if err != nil {
+			return nil, err
+		}
+		if results == nil {
+			return []interface{}{}, nil
+		}
+		return results.([]interface{}), nil
+	})
+}
+
+func (s *mockVpcService) ReadResource(d *schema.ResourceData, id string) (map[string]interface{}, error) {
+	if id == "" {
+		id = d.Id()
+	}
+	results, err := s.ReadResources(map[string]interface{}{
+		"VpcIds.1": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("vpc %s not exist ", id)
+	}
+	return results[0].(map[string]interface{}), nil
+}
+
+func (s *mockVpcService) RefreshResourceState(d *schema.ResourceData, target []string, timeout time.Duration, id string) *resource.StateChangeConf {
+	return &resource.StateChangeConf{
+		Pending:    []string{},
+		Target:     target,
+		Timeout:    timeout,
+		MinTimeout: 10 * time.Millisecond,
+		Refresh: func() (interface{}, string, error) {
+			data, err := s.ReadResource(d, id)
+			if err != nil {
+				return nil, "", err
+			}
+			return data, data["Status"].(string), nil
+		},
+	}
+}
+
+func (s *mockVpcService) WithResourceResponseHandlers(data map[string]interface{}) []ResourceResponseHandler {
+	return []ResourceResponseHandler{}
+}
+
+func (s *mockVpcService) CreateResource(d *schema.ResourceData, r *schema.Resource) []Callback {
+	return []Callback{{
+		Call: SdkCall{
+			Action:      "CreateVpc",
+			ConvertMode: RequestConvertAll,
+			ExecuteCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (*map[string]interface{}, error) {
+				return s.Client.UniversalClient.DoCall(mockVpcUniversalInfo(call.Action), call.SdkParam)
+			},
+			AfterCall: func(d *schema.ResourceData, client *SdkClient, resp *map[string]interface{}, call SdkCall) error {
+				id, _ := ObtainSdkValue("Result.VpcId", *resp)
+				d.SetId(id.(string))
+				return nil
+			},
+			Refresh: &StateRefresh{
+				Target:  []string{"Available"},
+				Timeout: time.Minute,
+			},
+		},
+	}}
+}
+
+func (s *mockVpcService) ModifyResource(d *schema.ResourceData, r *schema.Resource) []Callback {
+	return []Callback{}
+}
+
+func (s *mockVpcService) RemoveResource(d *schema.ResourceData, r *schema.Resource) []Callback {
+	return []Callback{{
+		Call: SdkCall{
+			Action:      "DeleteVpc",
+			ConvertMode: RequestConvertIgnore,
+			SdkParam: &map[string]interface{}{
+				"VpcId": d.Id(),
+			},
+			ExecuteCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (*map[string]interface{}, error) {
+				return s.Client.UniversalClient.DoCall(mockVpcUniversalInfo(call.Action), call.SdkParam)
+			},
+		},
+	}}
+}
+
+func (s *mockVpcService) DatasourceResources(d *schema.ResourceData, r *schema.Resource) DataSourceInfo {
+	return DataSourceInfo{
+		NameField:    "VpcName",
+		IdField:      "VpcId",
+		CollectField: "vpcs",
+	}
+}
+
+func (s *mockVpcService) ReadResourceId(id string) string {
+	return id
+}
+
+func mockVpcUniversalInfo(action string) UniversalInfo {
+	return UniversalInfo{
+		ServiceName: "vpc",
+		Version:     "2020-04-01",
+		HttpMethod:  GET,
+		Action:      action,
+	}
+}
+
+func mockVpcResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"vpc_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cidr_block": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func newMockVpcService(t *testing.T) (*MockServer, *mockVpcService) {
+	server := NewMockServer()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, &mockVpcService{Client: client}
+}
+
+func Test_MockServer_UniversalCall(t *testing.T) {
+	server, svc := newMockVpcService(t)
+	defer server.Close()
+
+	server.On("DescribeVpcs", MockResponse{
+		Result: map[string]interface{}{
+			"Vpcs": []interface{}{
+				map[string]interface{}{"VpcId": "vpc-1", "Status": "Available"},
+			},
+		},
+	})
+	data, err := svc.ReadResource(nil, "vpc-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Available", data["Status"])
+
+	requests := server.Requests("DescribeVpcs")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "vpc", requests[0].Service)
+	assert.Equal(t, "2020-04-01", requests[0].Version)
+	assert.Equal(t, "vpc-1", requests[0].Params["VpcIds.1"])
+}
+
+func Test_MockServer_Error(t *testing.T) {
+	server, svc := newMockVpcService(t)
+	defer server.Close()
+
+	server.On("DescribeVpcs", MockResponse{
+		Error: &MockError{
+			Code:    "InvalidVpc.NotFound",
+			Message: "The specified vpc is not found.",
+		},
+	})
+	_, err := svc.ReadResource(nil, "vpc-1")
+	assert.NotNil(t, err)
+	assert.True(t, ResourceNotFoundError(err))
+
+	_, err = svc.Client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcAttributes"), nil)
+	assert.NotNil(t, err)
+}
+
+func Test_MockServer_PageQuery(t *testing.T) {
+	server, svc := newMockVpcService(t)
+	defer server.Close()
+
+	server.On("DescribeVpcs",
+		MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-1"}, map[string]interface{}{"VpcId": "vpc-2"},
+		}}},
+		MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-3"},
+		}}},
+	)
+	data, err := svc.ReadResources(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(data))
+
+	requests := server.Requests("DescribeVpcs")
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "1", requests[0].Params["PageNumber"])
+	assert.Equal(t, "2", requests[1].Params["PageNumber"])
+}
+
+func Test_MockServer_DispatcherCreateAndDelete(t *testing.T) {
+	server, svc := newMockVpcService(t)
+	defer server.Close()
+
+	server.On("CreateVpc", MockResponse{Result: map[string]interface{}{"VpcId": "vpc-new"}})
+	server.On("DescribeVpcs",
+		MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-new", "Status": "Pending"},
+		}}},
+		MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+			map[string]interface{}{"VpcId": "vpc-new", "Status": "Available", "VpcName": "foo", "CidrBlock": "172.16.0.0/16"},
+		}}},
+	)
+	server.On("DeleteVpc", MockResponse{Result: map[string]interface{}{}})
+
+	r := mockVpcResource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"vpc_name":   "foo",
+		"cidr_block": "172.16.0.0/16",
+	})
+	err := DefaultDispatcher().Create(svc, d, r)
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc-new", d.Id())
+
+	createRequests := server.Requests("CreateVpc")
+	assert.Equal(t, 1, len(createRequests))
+	assert.Equal(t, "foo", createRequests[0].Params["VpcName"])
+	assert.Equal(t, "172.16.0.0/16", createRequests[0].Params["CidrBlock"])
+
+	err = DefaultDispatcher().Read(svc, d, r)
+	assert.Nil(t, err)
+	assert.Equal(t, "Available", d.Get("status"))
+
+	err = DefaultDispatcher().Delete(svc, d, r)
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc-new", server.Requests("DeleteVpc")[0].Params["VpcId"])
+}
+
+func Test_MockServer_LoadFixturesFile(t *testing.T) {
+	server, svc := newMockVpcService(t)
+	defer server.Close()
+
+	err := server.LoadFixturesFile("testdata/mock_vpc_fixtures.json")
+	assert.Nil(t, err)
+	data, err := svc.ReadResources(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(data))
+	assert.Equal(t, "vpc-fixture", data[0].(map[string]interface{})["VpcId"])
+}
@@ -1,5 +1,13 @@
 package common
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
 type PageCall func(map[string]interface{}) ([]interface{}, error)
 
 func WithPageOffsetQuery(condition map[string]interface{}, limitParam string, pageParam string, limit int, start int, call PageCall) (data []interface{}, err error) {
@@ -78,3 +86,195 @@ func WithNextTokenQuery(condition map[string]interface{}, maxResultsParam string
 	}
 	return data, err
 }
+
+// PageTotalCall return the items of a page and the TotalCount of all pages
+type PageTotalCall func(map[string]interface{}) ([]interface{}, int, error)
+
+// WithParallelPageNumberQuery read TotalCount from the first page and fetch the remaining pages concurrently,
+// every call has its own copy of condition
+func WithParallelPageNumberQuery(resourceService ResourceService, condition map[string]interface{}, pageSizeParam string, pageNumParam string, pageSize int, initPageNumber int, call PageTotalCall) (data []interface{}, err error) {
+	return withParallelPages(resourceService, condition, pageSize, func(m map[string]interface{}, page int) {
+		m[pageSizeParam] = pageSize
+		m[pageNumParam] = initPageNumber + page
+	}, call)
+}
+
+// WithParallelPageOffsetQuery read TotalCount from the first page and fetch the remaining pages concurrently,
+// every call has its own copy of condition
+func WithParallelPageOffsetQuery(resourceService ResourceService, condition map[string]interface{}, limitParam string, pageParam string, limit int, start int, call PageTotalCall) (data []interface{}, err error) {
+	return withParallelPages(resourceService, condition, limit, func(m map[string]interface{}, page int) {
+		m[limitParam] = limit
+		m[pageParam] = start + page*limit
+	}, call)
+}
+
+// withParallelPages the pages are limited by max_sync_concurrency and the qps of the data rate_limit of the service,
+// the current goroutine always fetches pages, so it never waits for the goroutines of its caller
+func withParallelPages(resourceService ResourceService, condition map[string]interface{}, pageSize int, setPage func(map[string]interface{}, int), call PageTotalCall) (data []interface{}, err error) {
+	if condition == nil {
+		condition = map[string]interface{}{}
+	}
+	client := resourceService.GetClient()
+	ctx, cancel := context.WithCancel(client.StopContext())
+	defer cancel()
+
+	setPage(condition, 0)
+	first, total, err := call(condition)
+	if err != nil {
+		return first, err
+	}
+	if len(first) < pageSize || (total > 0 && total <= len(first)) {
+		return first, nil
+	}
+	if total <= 0 {
+		// TotalCount is not returned, fetch pages one by one
+		data = first
+		for page := 1; ; page++ {
+			setPage(condition, page)
+			d, _, e := call(condition)
+			if e != nil {
+				return data, e
+			}
+			data = append(data, d...)
+			if len(d) < pageSize {
+				return data, nil
+			}
+		}
+	}
+	pageCount := (total + pageSize - 1) / pageSize
+	pages := make([][]interface{}, pageCount)
+	pages[0] = first
+
+	var (
+		lock     sync.Mutex
+		next     = 1
+		wg       sync.WaitGroup
+		rate     = client.rate(RateLimitServiceName(resourceService), RateOperationData)
+		sem      = client.syncLimit()
+		firstErr error
+	)
+	fetch := func() {
+		for {
+			lock.Lock()
+			page := next
+			next++
+			lock.Unlock()
+			if page >= pageCount || ctx.Err() != nil {
+				return
+			}
+			if rate != nil && rate.Limiter != nil {
+				if e := rate.Limiter.Wait(ctx); e != nil {
+					return
+				}
+			}
+			m := make(map[string]interface{}, len(condition))
+			for k, v := range condition {
+				m[k] = v
+			}
+			setPage(m, page)
+			d, _, e := call(m)
+			lock.Lock()
+			if e != nil && firstErr == nil {
+				firstErr = e
+				cancel()
+			}
+			pages[page] = d
+			lock.Unlock()
+		}
+	}
+	for i := 2; i < pageCount && sem != nil && sem.TryAcquire(1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+			fetch()
+		}()
+	}
+	fetch()
+	wg.Wait()
+
+	if firstErr != nil {
+		return data, firstErr
+	}
+	if err = ctx.Err(); err != nil {
+		return data, err
+	}
+	for _, d := range pages {
+		data = append(data, d...)
+	}
+	return data, nil
+}
+
+// BatchCall read the items of a batch of ids
+type BatchCall func(ids []string) error
+
+// WithBatchQuery call with the distinct non-empty ids, at most batchSize ids at a time.
+// The failed batches do not stop the others, their errors are joined into the returned error
+func WithBatchQuery(ids []string, batchSize int, call BatchCall) error {
+	batches := idBatches(ids, batchSize)
+	errs := make([]error, len(batches))
+	for i, batch := range batches {
+		errs[i] = call(batch)
+	}
+	return joinBatchErrors(batches, errs)
+}
+
+// WithParallelBatchQuery same as WithBatchQuery but the batches are called concurrently, limited by the
+// max_sync_concurrency of client. The batches not started are failed when the provider is stopped
+func WithParallelBatchQuery(client *SdkClient, ids []string, batchSize int, call BatchCall) error {
+	var (
+		wg      sync.WaitGroup
+		batches = idBatches(ids, batchSize)
+		errs    = make([]error, len(batches))
+	)
+	for i, batch := range batches {
+		if err := client.Acquire(); err != nil {
+			errs[i] = err
+			continue
+		}
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			defer client.Release()
+			errs[i] = call(batch)
+		}(i, batch)
+	}
+	wg.Wait()
+	return joinBatchErrors(batches, errs)
+}
+
+// idBatches the distinct non-empty ids split by batchSize
+func idBatches(ids []string, batchSize int) (batches [][]string) {
+	var (
+		distinct []string
+		seen     = make(map[string]bool)
+	)
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		distinct = append(distinct, id)
+	}
+	for start := 0; start < len(distinct); start += batchSize {
+		end := start + batchSize
+		if end > len(distinct) {
+			end = len(distinct)
+		}
+		batches = append(batches, distinct[start:end])
+	}
+	return batches
+}
+
+func joinBatchErrors(batches [][]string, errs []error) error {
+	var messages []string
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("[%s]: %v", strings.Join(batches[i], ","), err))
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
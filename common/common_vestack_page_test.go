@@ -0,0 +1,87 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockPagedVpcs(total int, withTotal bool) MockHandler {
+	return func(req *MockRequest) *MockResponse {
+		pageNumber, _ := strconv.Atoi(fmt.Sprintf("%v", req.Params["PageNumber"]))
+		pageSize, _ := strconv.Atoi(fmt.Sprintf("%v", req.Params["PageSize"]))
+		var vpcs []interface{}
+		for i := (pageNumber - 1) * pageSize; i < pageNumber*pageSize && i < total; i++ {
+			vpcs = append(vpcs, map[string]interface{}{"VpcId": fmt.Sprintf("vpc-%d", i)})
+		}
+		result := map[string]interface{}{"Vpcs": vpcs}
+		if withTotal {
+			result["TotalCount"] = total
+		}
+		return &MockResponse{Result: result}
+	}
+}
+
+func readPagedVpcs(vpc *mockVpcService) ([]interface{}, error) {
+	return WithParallelPageNumberQuery(vpc, nil, "PageSize", "PageNumber", 2, 1, func(m map[string]interface{}) ([]interface{}, int, error) {
+		resp, err := vpc.Client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &m)
+		if err != nil {
+			return nil, 0, err
+		}
+		results, err := ObtainSdkValue("Result.Vpcs", *resp)
+		if err != nil || results == nil {
+			return nil, 0, err
+		}
+		total, _ := ObtainSdkValue("Result.TotalCount", *resp)
+		count, _ := total.(float64)
+		return results.([]interface{}), int(count), nil
+	})
+}
+
+func Test_WithParallelPageNumberQuery(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+
+	server.OnFunc("DescribeVpcs", mockPagedVpcs(7, true))
+	data, err := readPagedVpcs(vpc)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(data))
+	for i, v := range data {
+		assert.Equal(t, fmt.Sprintf("vpc-%d", i), v.(map[string]interface{})["VpcId"])
+	}
+	assert.Equal(t, 4, len(server.Requests("DescribeVpcs")))
+
+	// pages one by one without TotalCount
+	server.Reset()
+	server.OnFunc("DescribeVpcs", mockPagedVpcs(4, false))
+	data, err = readPagedVpcs(vpc)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(data))
+	assert.Equal(t, 3, len(server.Requests("DescribeVpcs")))
+
+	server.Reset()
+	server.OnFunc("DescribeVpcs", mockPagedVpcs(6, true), func(req *MockRequest) *MockResponse {
+		return &MockResponse{StatusCode: 400, Error: &MockError{Code: "InvalidParameter", Message: "mock"}}
+	})
+	_, err = readPagedVpcs(vpc)
+	assert.NotNil(t, err)
+}
+
+func Test_WithBatchQuery(t *testing.T) {
+	var batches [][]string
+	err := WithBatchQuery([]string{"a", "b", "", "a", "c", "d", "e"}, 2, func(ids []string) error {
+		batches = append(batches, ids)
+		if ids[0] == "c" {
+			return errors.New("mock")
+		}
+		return nil
+	})
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, batches)
+	assert.Equal(t, "[c,d]: mock", err.Error())
+	assert.Nil(t, WithBatchQuery(nil, 2, func(ids []string) error {
+		return errors.New("never called")
+	}))
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	RateOperationCreate = "create"
+	RateOperationRead   = "read"
+	RateOperationUpdate = "update"
+	RateOperationDelete = "delete"
+	RateOperationData   = "data"
+)
+
+var RateOperations = []string{
+	RateOperationCreate,
+	RateOperationRead,
+	RateOperationUpdate,
+	RateOperationDelete,
+	RateOperationData,
+}
+
+// RateLimit provider rate_limit block, empty Service or Operation match all
+type RateLimit struct {
+	Service        string
+	Operation      string
+	Qps            float64
+	Burst          int
+	MaxConcurrency int
+}
+
+// rateLimits rates of one provider instance, keyed by service/operation
+type rateLimits map[string]*Rate
+
+func rateLimitKey(service, operation string) string {
+	return service + "/" + operation
+}
+
+func newRateLimits(limits []RateLimit) (rateLimits, error) {
+	result := make(rateLimits)
+	for _, l := range limits {
+		if l.Operation != "" && !isRateOperation(l.Operation) {
+			return nil, fmt.Errorf("rate_limit operation must be one of %s, got %s", strings.Join(RateOperations, ","), l.Operation)
+		}
+		key := rateLimitKey(l.Service, l.Operation)
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("duplicate rate_limit of service %q operation %q", l.Service, l.Operation)
+		}
+		result[key] = NewRate(l.Qps, l.Burst, int64(l.MaxConcurrency))
+	}
+	return result, nil
+}
+
+func isRateOperation(operation string) bool {
+	for _, op := range RateOperations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// get the most specific rate of service and operation
+func (r rateLimits) get(service, operation string) *Rate {
+	for _, key := range []string{
+		rateLimitKey(service, operation),
+		rateLimitKey(service, ""),
+		rateLimitKey("", operation),
+		rateLimitKey("", ""),
+	} {
+		if v, ok := r[key]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// rateLimitServiceAliases the packages under vestack which call the api of another service
+var rateLimitServiceAliases = map[string]string{
+	"eip": "vpc",
+}
+
+// RateLimitServiceName the service of resource service is the api service of the package under vestack,
+// e.g. github.com/volcengine/terraform-provider-vestack/vestack/ecs/ecs_instance is ecs and vestack/eip/eip_address is vpc
+func RateLimitServiceName(resourceService interface{}) string {
+	t := reflect.TypeOf(resourceService)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return rateLimitServiceOfPackage(t.PkgPath())
+}
+
+func rateLimitServiceOfPackage(pkgPath string) string {
+	parts := strings.Split(pkgPath, "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == "vestack" {
+			if alias, ok := rateLimitServiceAliases[parts[i+1]]; ok {
+				return alias
+			}
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// waitRate wait limiter and acquire semaphore of r, release must be called when err is nil
+func waitRate(ctx context.Context, r *Rate) (release func(), err error) {
+	release = func() {}
+	if r == nil {
+		return release, nil
+	}
+	if r.Limiter != nil {
+		if err = r.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if r.Semaphore != nil {
+		if err = r.Semaphore.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+		release = func() {
+			r.Semaphore.Release(1)
+		}
+	}
+	return release, nil
+}
+
+// scopedRates the copies of NewRate rates owned by one client
+type scopedRates struct {
+	lock  sync.Mutex
+	rates map[*Rate]*Rate
+}
+
+func newScopedRates() *scopedRates {
+	return &scopedRates{
+		rates: make(map[*Rate]*Rate),
+	}
+}
+
+// scopedRate the copy of r owned by this client, rates not built by NewRate are shared
+func (c *SdkClient) scopedRate(r *Rate) *Rate {
+	if r == nil || !r.scoped || c == nil || c.rates == nil {
+		return r
+	}
+	c.rates.lock.Lock()
+	defer c.rates.lock.Unlock()
+	if v, ok := c.rates.rates[r]; ok {
+		return v
+	}
+	v := NewRate(r.qps, r.burst, r.concurrency)
+	c.rates.rates[r] = v
+	return v
+}
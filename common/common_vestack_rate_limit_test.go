@@ -0,0 +1,62 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RateLimits(t *testing.T) {
+	limits, err := newRateLimits([]RateLimit{
+		{Qps: 10},
+		{Service: "ecs", Qps: 5},
+		{Service: "ecs", Operation: RateOperationCreate, MaxConcurrency: 2},
+		{Operation: RateOperationData, Qps: 1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, limits[rateLimitKey("ecs", RateOperationCreate)], limits.get("ecs", RateOperationCreate))
+	assert.Equal(t, limits[rateLimitKey("ecs", "")], limits.get("ecs", RateOperationData))
+	assert.Equal(t, limits[rateLimitKey("", RateOperationData)], limits.get("vpc", RateOperationData))
+	assert.Equal(t, limits[rateLimitKey("", "")], limits.get("vpc", RateOperationRead))
+	assert.NotNil(t, limits.get("ecs", RateOperationCreate).Semaphore)
+	assert.Nil(t, limits.get("ecs", RateOperationCreate).Limiter)
+
+	_, err = newRateLimits([]RateLimit{{Operation: "list"}})
+	assert.NotNil(t, err)
+	_, err = newRateLimits([]RateLimit{{Service: "vpc"}, {Service: "vpc"}})
+	assert.NotNil(t, err)
+}
+
+func Test_RateLimitServiceName(t *testing.T) {
+	assert.Equal(t, "", RateLimitServiceName(&mockVpcService{}))
+	assert.Equal(t, "", RateLimitServiceName(nil))
+	assert.Equal(t, "ecs", rateLimitServiceOfPackage("github.com/volcengine/terraform-provider-vestack/vestack/ecs/ecs_instance"))
+	assert.Equal(t, "vpc", rateLimitServiceOfPackage("github.com/volcengine/terraform-provider-vestack/vestack/eip/eip_address"))
+}
+
+func Test_Dispatcher_ProviderRateLimit(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	config := server.Config()
+	config.StopContext = ctx
+	config.RateLimits = []RateLimit{{Operation: RateOperationRead, MaxConcurrency: 1}}
+	client, err := config.Client()
+	assert.Nil(t, err)
+	svc := &mockVpcService{Client: client}
+
+	r := mockVpcResource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("vpc-1")
+	// the only read slot is taken, read wait until stop context is done
+	sem := client.rate("", RateOperationRead).Semaphore
+	assert.True(t, sem.TryAcquire(1))
+	err = DefaultDispatcher().Read(svc, d, r)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(server.Requests("")))
+	sem.Release(1)
+}
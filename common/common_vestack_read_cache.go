@@ -0,0 +1,159 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/volcengine/terraform-provider-vestack/logger"
+)
+
+// readCache results of ReadResources of one provider run and region, keyed by action and condition.
+// Only the goroutines of Dispatcher Read and Data use it, the state refresh of create, update and delete
+// always read the api, and every write of the dispatcher drops the cached results
+type readCache struct {
+	lock       sync.Mutex
+	generation uint64
+	entries    map[string]*readCacheEntry
+	scopes     map[uint64]int
+}
+
+type readCacheEntry struct {
+	done chan struct{}
+	data []interface{}
+	err  error
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		entries: make(map[string]*readCacheEntry),
+		scopes:  make(map[uint64]int),
+	}
+}
+
+// bind enable the cache for current goroutine until unbind is called
+func (r *readCache) bind() (unbind func()) {
+	gid := logger.GetGID()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.scopes[gid]++
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if r.scopes[gid]--; r.scopes[gid] <= 0 {
+			delete(r.scopes, gid)
+		}
+	}
+}
+
+func (r *readCache) bound() bool {
+	gid := logger.GetGID()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.scopes[gid] > 0
+}
+
+// invalidate drop all results, the reads in flight are not cached
+func (r *readCache) invalidate() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.generation++
+	r.entries = make(map[string]*readCacheEntry)
+}
+
+// read the concurrent reads of the same key share one call, errors are never cached
+func (r *readCache) read(action, key string, call func() ([]interface{}, error)) ([]interface{}, error) {
+	r.lock.Lock()
+	if entry, ok := r.entries[key]; ok {
+		r.lock.Unlock()
+		<-entry.done
+		logger.DebugInfo("read cache hit: %s", action)
+		return copyReadResult(entry.data), entry.err
+	}
+	entry := &readCacheEntry{
+		done: make(chan struct{}),
+	}
+	r.entries[key] = entry
+	generation := r.generation
+	r.lock.Unlock()
+
+	entry.data, entry.err = call()
+
+	r.lock.Lock()
+	if (entry.err != nil || generation != r.generation) && r.entries[key] == entry {
+		delete(r.entries, key)
+	}
+	r.lock.Unlock()
+	close(entry.done)
+	return copyReadResult(entry.data), entry.err
+}
+
+func readCacheKey(action string, condition map[string]interface{}) (string, error) {
+	// the keys of map are sorted by json
+	b, err := json.Marshal(condition)
+	if err != nil {
+		return "", err
+	}
+	return action + ":" + string(b), nil
+}
+
+// copyReadResult the callers may modify the result, every caller has a copy of the maps and slices
+func copyReadResult(data []interface{}) []interface{} {
+	if data == nil {
+		return nil
+	}
+	return copyReadValue(data).([]interface{})
+}
+
+func copyReadValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = copyReadValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = copyReadValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// CachedRead read by call, the result is cached by action and condition when read_cache of provider is enabled
+// and current goroutine is reading a resource or data source
+func (c *SdkClient) CachedRead(action string, condition map[string]interface{}, call func() ([]interface{}, error)) ([]interface{}, error) {
+	if c == nil || c.readCache == nil || !c.readCache.bound() {
+		return call()
+	}
+	key, err := readCacheKey(action, condition)
+	if err != nil {
+		return call()
+	}
+	return c.readCache.read(action, key, call)
+}
+
+// CachedReadResources ReadResources of resourceService shared by data sources and resource reads
+func CachedReadResources(resourceService ResourceService, condition map[string]interface{}) ([]interface{}, error) {
+	return resourceService.GetClient().CachedRead(fmt.Sprintf("%T", resourceService), condition, func() ([]interface{}, error) {
+		return resourceService.ReadResources(condition)
+	})
+}
+
+func (c *SdkClient) bindReadCache() (unbind func()) {
+	if c == nil || c.readCache == nil {
+		return func() {}
+	}
+	return c.readCache.bind()
+}
+
+func (c *SdkClient) invalidateReadCache() {
+	if c == nil || c.readCache == nil {
+		return
+	}
+	c.readCache.invalidate()
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadCache(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-1"},
+	}}})
+	config := server.Config()
+	config.ReadCache = true
+	client, err := config.Client()
+	assert.Nil(t, err)
+	vpc := &mockVpcService{Client: client}
+
+	r := mockVpcDataSource()
+	for i := 0; i < 3; i++ {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+		assert.Equal(t, "vpc-1", d.Get("vpcs.0.vpc_id"))
+	}
+	assert.Equal(t, 1, len(server.Requests("DescribeVpcs")))
+
+	// the result of every caller is a copy
+	unbind := client.bindReadCache()
+	data, err := CachedReadResources(vpc, map[string]interface{}{})
+	assert.Nil(t, err)
+	data[0].(map[string]interface{})["VpcId"] = "vpc-changed"
+	data, err = CachedReadResources(vpc, map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc-1", data[0].(map[string]interface{})["VpcId"])
+	unbind()
+	assert.Equal(t, 1, len(server.Requests("DescribeVpcs")))
+
+	// not cached out of reads, e.g. waiting for the status of a resource
+	_, err = CachedReadResources(vpc, map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(server.Requests("DescribeVpcs")))
+
+	client.invalidateReadCache()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+	assert.Equal(t, 3, len(server.Requests("DescribeVpcs")))
+
+	// disabled by default
+	server, vpc = newMockVpcService(t)
+	defer server.Close()
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}}})
+	for i := 0; i < 2; i++ {
+		d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		assert.Nil(t, DefaultDispatcher().Data(vpc, d, r))
+	}
+	assert.Equal(t, 2, len(server.Requests("DescribeVpcs")))
+}
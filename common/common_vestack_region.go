@@ -0,0 +1,119 @@
+package common
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const RegionField = "region"
+
+// ResourceRegionOverride add the optional `region` argument to resource, every operation of the resource
+// uses the client of the region. The provider region is used and saved when it is not set
+func ResourceRegionOverride(resource *schema.Resource) *schema.Resource {
+	if _, ok := resource.Schema[RegionField]; ok {
+		return resource
+	}
+	resource.Schema[RegionField] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "The region of the resource. Default is the region of the provider.",
+	}
+
+	if create := resource.Create; create != nil {
+		resource.Create = func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return err
+			}
+			if err = create(d, client); err != nil {
+				return err
+			}
+			return setRegion(d, client)
+		}
+	}
+	if read := resource.Read; read != nil {
+		resource.Read = func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return err
+			}
+			if err = read(d, client); err != nil {
+				return err
+			}
+			return setRegion(d, client)
+		}
+	}
+	if update := resource.Update; update != nil {
+		resource.Update = func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return err
+			}
+			return update(d, client)
+		}
+	}
+	if del := resource.Delete; del != nil {
+		resource.Delete = func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return err
+			}
+			return del(d, client)
+		}
+	}
+	if exists := resource.Exists; exists != nil {
+		resource.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return false, err
+			}
+			return exists(d, client)
+		}
+	}
+	if resource.Importer != nil && resource.Importer.State != nil {
+		state := resource.Importer.State
+		resource.Importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			client, err := regionClient(d, meta)
+			if err != nil {
+				return nil, err
+			}
+			return state(d, client)
+		}
+	}
+	if customizeDiff := resource.CustomizeDiff; customizeDiff != nil {
+		resource.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+			client, ok := meta.(*SdkClient)
+			if ok {
+				region, _ := diff.Get(RegionField).(string)
+				c, err := client.ForRegion(region)
+				if err != nil {
+					return err
+				}
+				return customizeDiff(diff, c)
+			}
+			return customizeDiff(diff, meta)
+		}
+	}
+	return resource
+}
+
+func regionClient(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	client, ok := meta.(*SdkClient)
+	if !ok {
+		return meta, nil
+	}
+	region, _ := d.Get(RegionField).(string)
+	return client.ForRegion(region)
+}
+
+func setRegion(d *schema.ResourceData, client interface{}) error {
+	c, ok := client.(*SdkClient)
+	if !ok || d.Id() == "" {
+		return nil
+	}
+	if region, _ := d.Get(RegionField).(string); region == "" {
+		return d.Set(RegionField, c.Region)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/request"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/volcengineerr"
+)
+
+type ErrorCategory int
+
+const (
+	ErrorCategoryUnknown ErrorCategory = iota
+	ErrorCategoryThrottled
+	ErrorCategoryConflict
+	ErrorCategoryTransient
+	ErrorCategoryNotFound
+	ErrorCategoryValidation
+)
+
+func (c ErrorCategory) String() string {
+	switch c {
+	case ErrorCategoryThrottled:
+		return "throttled"
+	case ErrorCategoryConflict:
+		return "conflict"
+	case ErrorCategoryTransient:
+		return "transient"
+	case ErrorCategoryNotFound:
+		return "not_found"
+	case ErrorCategoryValidation:
+		return "validation"
+	default:
+		return "unknown"
+	}
+}
+
+// error code keywords of every category, matched in order of classifyRules
+var classifyRules = []struct {
+	category ErrorCategory
+	keywords []string
+}{
+	{ErrorCategoryThrottled, []string{"flowlimitexceeded", "throttling", "toomanyrequests", "requestlimitexceeded", "quotaexceeded.apicall"}},
+	{ErrorCategoryNotFound, []string{"notfound", "not_found", "notexist"}},
+	{ErrorCategoryConflict, []string{"inuse", "invalidstatus", "incorrectstatus", "dependencyviolation", "lockfailed", "concurrentoperation"}},
+	{ErrorCategoryTransient, []string{"internalerror", "internalservice", "serviceunavailable", "timeout", "requesterror"}},
+}
+
+var (
+	retryableCodes     = make(map[string]ErrorCategory)
+	retryableCodesLock sync.RWMutex
+)
+
+// RegisterRetryableCodes classify the error codes of a service which are not matched by keywords,
+// usually called in init of the service package
+func RegisterRetryableCodes(category ErrorCategory, codes ...string) {
+	retryableCodesLock.Lock()
+	defer retryableCodesLock.Unlock()
+	for _, code := range codes {
+		retryableCodes[code] = category
+	}
+}
+
+// ClassifyError parse the code and status of volcengineerr.RequestFailure,
+// errors which lost the type (e.g. formatted by %s) are classified by message
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryUnknown
+	}
+	var (
+		code    string
+		status  int
+		failure volcengineerr.RequestFailure
+		e       volcengineerr.Error
+	)
+	if errors.As(err, &failure) {
+		code = failure.Code()
+		status = failure.StatusCode()
+	} else if errors.As(err, &e) {
+		code = e.Code()
+	}
+	if code == request.CanceledErrorCode || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCategoryUnknown
+	}
+
+	retryableCodesLock.RLock()
+	category, ok := retryableCodes[code]
+	retryableCodesLock.RUnlock()
+	if ok {
+		return category
+	}
+
+	if code != "" {
+		lowerCode := strings.ToLower(code)
+		for _, rule := range classifyRules {
+			for _, keyword := range rule.keywords {
+				if strings.Contains(lowerCode, strings.ToLower(keyword)) {
+					return rule.category
+				}
+			}
+		}
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorCategoryThrottled
+	case status == http.StatusNotFound:
+		return ErrorCategoryNotFound
+	case status == http.StatusConflict:
+		return ErrorCategoryConflict
+	case status >= http.StatusInternalServerError:
+		return ErrorCategoryTransient
+	case status >= http.StatusBadRequest:
+		return ErrorCategoryValidation
+	}
+
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "flowlimitexceeded") {
+		return ErrorCategoryThrottled
+	}
+	if isNotFoundMessage(message) {
+		return ErrorCategoryNotFound
+	}
+	return ErrorCategoryUnknown
+}
+
+type RetryPolicy struct {
+	// MaxAttempts include the first call, 1 means no retry
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Categories retryable error categories
+	Categories []ErrorCategory
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+	Categories:  []ErrorCategory{ErrorCategoryThrottled, ErrorCategoryConflict, ErrorCategoryTransient},
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	category := ClassifyError(err)
+	for _, c := range p.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// nonIdempotentActionPrefixes the actions which create a new resource on every successful call
+var nonIdempotentActionPrefixes = []string{"Create", "Run", "Allocate", "Purchase"}
+
+// ForCall the policy of a call. The transient errors (e.g. timeout) of a non-idempotent action without ClientToken
+// are not retried, the failed call may have created the resource, retrying it creates a duplicate one.
+// The throttled and conflict errors are rejected before the call is processed, so they are still retried
+func (p RetryPolicy) ForCall(action string, param *map[string]interface{}) RetryPolicy {
+	if !isNonIdempotentAction(action) {
+		return p
+	}
+	if param != nil {
+		if token, ok := (*param)["ClientToken"].(string); ok && token != "" {
+			return p
+		}
+	}
+	var categories []ErrorCategory
+	for _, c := range p.Categories {
+		if c != ErrorCategoryTransient {
+			categories = append(categories, c)
+		}
+	}
+	p.Categories = categories
+	return p
+}
+
+func isNonIdempotentAction(action string) bool {
+	for _, prefix := range nonIdempotentActionPrefixes {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff exponential delay of attempt (begin with 1), jitter in [delay/2, delay]
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Retry call fn until it succeed, return an error which is not retryable, or attempts exhausted
+func (p RetryPolicy) Retry(ctx context.Context, action string, fn func() error) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) {
+			return err
+		}
+		delay := p.backoff(attempt)
+		logger.DebugInfo("retry %s after %s, attempt %d, %s error: %s", action, delay, attempt, ClassifyError(err), err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, retry cancelled: %v", err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryPolicy the retry policy of provider, DefaultRetryPolicy when not configured
+func (c *SdkClient) RetryPolicy() RetryPolicy {
+	if c == nil || c.retryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+	return *c.retryPolicy
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/volcengineerr"
+)
+
+func Test_ClassifyError(t *testing.T) {
+	failure := func(code string, status int) error {
+		return volcengineerr.NewRequestFailure(volcengineerr.New(code, "mock message", nil), status, "req-1")
+	}
+	cases := []struct {
+		err      error
+		category ErrorCategory
+	}{
+		{failure("FlowLimitExceeded", 400), ErrorCategoryThrottled},
+		{failure("AccountFlowLimitExceeded", 429), ErrorCategoryThrottled},
+		{failure("InvalidVpc.NotFound", 404), ErrorCategoryNotFound},
+		{failure("InvalidVpc.InUse", 400), ErrorCategoryConflict},
+		{failure("InvalidSubnet.InvalidStatus", 400), ErrorCategoryConflict},
+		{failure("InternalError", 500), ErrorCategoryTransient},
+		{failure("Unknown", 503), ErrorCategoryTransient},
+		{failure("InvalidParameter", 400), ErrorCategoryValidation},
+		{failure("MissingParameter", 400), ErrorCategoryValidation},
+		{fmt.Errorf("error on reading vpc: %w", failure("FlowLimitExceeded", 400)), ErrorCategoryThrottled},
+		{fmt.Errorf("vpc vpc-1 not exist "), ErrorCategoryNotFound},
+		{fmt.Errorf("InvalidParameter: cidr is invalid"), ErrorCategoryUnknown},
+		{volcengineerr.New("RequestError", "send request failed", nil), ErrorCategoryTransient},
+		{volcengineerr.New("RequestCanceled", "request context canceled", context.Canceled), ErrorCategoryUnknown},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.category, ClassifyError(c.err), c.err.Error())
+	}
+
+	assert.False(t, ResourceNotFoundError(failure("InvalidParameter", 400)))
+	assert.False(t, ResourceNotFoundError(fmt.Errorf("InvalidParameter: cidr is invalid")))
+	assert.True(t, ResourceNotFoundError(failure("InvalidVpc.NotFound", 400)))
+	assert.True(t, ResourceFlowLimitExceededError(failure("FlowLimitExceeded", 400)))
+
+	RegisterRetryableCodes(ErrorCategoryConflict, "MockOperation.Denied")
+	assert.Equal(t, ErrorCategoryConflict, ClassifyError(failure("MockOperation.Denied", 400)))
+}
+
+func Test_RetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, max := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		delay := p.backoff(attempt)
+		assert.True(t, delay >= max/2 && delay <= max, "attempt %d delay %s", attempt, delay)
+	}
+}
+
+func Test_CallProcess_Retry(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	config := server.Config()
+	config.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+		Categories:  DefaultRetryPolicy.Categories,
+	}
+	client, err := config.Client()
+	assert.Nil(t, err)
+	svc := &mockVpcService{Client: client}
+
+	server.On("CreateVpc",
+		MockResponse{Error: &MockError{Code: "FlowLimitExceeded", Message: "flow limit"}},
+		MockResponse{Error: &MockError{Code: "InvalidVpc.InvalidStatus", Message: "invalid status"}},
+		MockResponse{Result: map[string]interface{}{"VpcId": "vpc-new"}},
+	)
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-new", "Status": "Available"},
+	}}})
+	r := mockVpcResource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"cidr_block": "172.16.0.0/16",
+	})
+	err = DefaultDispatcher().Create(svc, d, r)
+	assert.Nil(t, err)
+	assert.Equal(t, "vpc-new", d.Id())
+	assert.Equal(t, 3, len(server.Requests("CreateVpc")))
+
+	// the transient error of create without ClientToken is not retried
+	server.Reset()
+	server.On("CreateVpc", MockResponse{StatusCode: 500, Error: &MockError{Code: "InternalError", Message: "internal"}})
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"cidr_block": "172.16.0.0/16",
+	})
+	err = DefaultDispatcher().Create(svc, d, r)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(server.Requests("CreateVpc")))
+
+	// validation error is not retried
+	server.Reset()
+	server.On("DeleteVpc", MockResponse{Error: &MockError{Code: "InvalidParameter", Message: "invalid vpc id"}})
+	err = DefaultDispatcher().Delete(svc, d, r)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(server.Requests("DeleteVpc")))
+}
+
+func Test_RetryPolicy_ForCall(t *testing.T) {
+	all := []ErrorCategory{ErrorCategoryThrottled, ErrorCategoryConflict, ErrorCategoryTransient}
+	policy := RetryPolicy{Categories: all}
+	noTransient := []ErrorCategory{ErrorCategoryThrottled, ErrorCategoryConflict}
+
+	assert.Equal(t, all, policy.ForCall("DeleteVpc", nil).Categories)
+	assert.Equal(t, noTransient, policy.ForCall("CreateVpc", nil).Categories)
+	assert.Equal(t, noTransient, policy.ForCall("RunInstances", &map[string]interface{}{"ClientToken": ""}).Categories)
+	assert.Equal(t, all, policy.ForCall("RunInstances", &map[string]interface{}{"ClientToken": "token-1"}).Categories)
+	// the policy of provider is not changed
+	assert.Equal(t, all, policy.Categories)
+}
+
+func Test_SdkRetryDisabled(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	server.On("DescribeVpcs", MockResponse{StatusCode: 503, Error: &MockError{Code: "ServiceUnavailable", Message: "unavailable"}})
+	_, err = client.UniversalClient.DoCall(mockVpcUniversalInfo("DescribeVpcs"), &map[string]interface{}{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(server.Requests("DescribeVpcs")))
+}
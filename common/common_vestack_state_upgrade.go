@@ -0,0 +1,194 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// StateMigration upgrade the raw state of a resource from Version to Version+1
+type StateMigration struct {
+	Version int
+	// Schema the schema of the resource in Version, the current schema is used when nil
+	Schema map[string]*schema.Schema
+	// Upgrade the raw state is the json state decoded into a map, e.g. sets and lists are []interface{}
+	Upgrade schema.StateUpgradeFunc
+}
+
+// ResourceStateMigrations declare the migrations of resource in order of version, the SchemaVersion of resource is
+// the version after the last migration. Every migration upgrades the state one version.
+func ResourceStateMigrations(resource *schema.Resource, migrations ...StateMigration) *schema.Resource {
+	for i, migration := range migrations {
+		if i > 0 && migration.Version != migrations[i-1].Version+1 {
+			panic(fmt.Sprintf("state migration version %d must follow version %d", migration.Version, migrations[i-1].Version))
+		}
+		s := migration.Schema
+		if s == nil {
+			s = resource.Schema
+		}
+		resource.StateUpgraders = append(resource.StateUpgraders, schema.StateUpgrader{
+			Version: migration.Version,
+			Type:    (&schema.Resource{Schema: s}).CoreConfigSchema().ImpliedType(),
+			Upgrade: migration.Upgrade,
+		})
+		resource.SchemaVersion = migration.Version + 1
+	}
+	return resource
+}
+
+// UpgradeState run the StateUpgraders of resource from version to the SchemaVersion of resource
+func UpgradeState(resource *schema.Resource, rawState map[string]interface{}, version int, meta interface{}) (map[string]interface{}, error) {
+	var err error
+	for _, upgrader := range resource.StateUpgraders {
+		if upgrader.Version < version {
+			continue
+		}
+		rawState, err = upgrader.Upgrade(rawState, meta)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade state from version %d error: %w", upgrader.Version, err)
+		}
+	}
+	return rawState, nil
+}
+
+// CheckStateSchema return error when rawState can not be decoded by the current schema of resource
+func CheckStateSchema(resource *schema.Resource, rawState map[string]interface{}) error {
+	b, err := json.Marshal(rawState)
+	if err != nil {
+		return err
+	}
+	_, err = ctyjson.Unmarshal(b, resource.CoreConfigSchema().ImpliedType())
+	return err
+}
+
+// StateUpgrades chain the upgrades into one
+func StateUpgrades(upgrades ...schema.StateUpgradeFunc) schema.StateUpgradeFunc {
+	return func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		var err error
+		for _, upgrade := range upgrades {
+			if rawState, err = upgrade(rawState, meta); err != nil {
+				return nil, err
+			}
+		}
+		return rawState, nil
+	}
+}
+
+// RenameField rename the field of from to the last part of to, the parts of the path are split by `.`,
+// e.g. `data_volumes.volume_name` rename volume_name of every data volume
+func RenameField(from, to string) schema.StateUpgradeFunc {
+	path := strings.Split(from, ".")
+	name := to[strings.LastIndex(to, ".")+1:]
+	return func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		err := walkStatePath(rawState, path, func(parent map[string]interface{}, key string) error {
+			if v, ok := parent[key]; ok {
+				delete(parent, key)
+				parent[name] = v
+			}
+			return nil
+		})
+		return rawState, err
+	}
+}
+
+// ConvertField convert the value of the field of path, e.g. the type of the field is changed.
+// The null values are converted too
+func ConvertField(path string, convert func(v interface{}) (interface{}, error)) schema.StateUpgradeFunc {
+	parts := strings.Split(path, ".")
+	return func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		err := walkStatePath(rawState, parts, func(parent map[string]interface{}, key string) error {
+			v, err := convert(parent[key])
+			if err != nil {
+				return fmt.Errorf("convert %s error: %w", path, err)
+			}
+			parent[key] = v
+			return nil
+		})
+		return rawState, err
+	}
+}
+
+// SetToList the json of set and list are both arrays, the elements are sorted by less when it is not nil,
+// e.g. the data volumes sorted by volume name
+func SetToList(path string, less func(a, b interface{}) bool) schema.StateUpgradeFunc {
+	return ConvertField(path, func(v interface{}) (interface{}, error) {
+		list, ok := v.([]interface{})
+		if !ok || less == nil {
+			return v, nil
+		}
+		result := append([]interface{}{}, list...)
+		sort.SliceStable(result, func(i, j int) bool {
+			return less(result[i], result[j])
+		})
+		return result, nil
+	})
+}
+
+// ListToSet drop the duplicate elements of the list
+func ListToSet(path string) schema.StateUpgradeFunc {
+	return ConvertField(path, func(v interface{}) (interface{}, error) {
+		list, ok := v.([]interface{})
+		if !ok {
+			return v, nil
+		}
+		var result []interface{}
+		for _, item := range list {
+			duplicate := false
+			for _, exist := range result {
+				if reflect.DeepEqual(item, exist) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	})
+}
+
+// ChangeId change the format of the id, e.g. a composite id of the parent id and the id
+func ChangeId(convert func(id string, rawState map[string]interface{}) (string, error)) schema.StateUpgradeFunc {
+	return func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		id, _ := rawState["id"].(string)
+		if id == "" {
+			return rawState, nil
+		}
+		newId, err := convert(id, rawState)
+		if err != nil {
+			return nil, fmt.Errorf("change id %s error: %w", id, err)
+		}
+		rawState["id"] = newId
+		return rawState, nil
+	}
+}
+
+// walkStatePath call fn with the parent map and the key of every match of path,
+// the lists and sets of blocks in path are walked into every element
+func walkStatePath(state map[string]interface{}, path []string, fn func(parent map[string]interface{}, key string) error) error {
+	if state == nil || len(path) == 0 {
+		return nil
+	}
+	if len(path) == 1 {
+		return fn(state, path[0])
+	}
+	switch v := state[path[0]].(type) {
+	case map[string]interface{}:
+		return walkStatePath(v, path[1:], fn)
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				if err := walkStatePath(m, path[1:], fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
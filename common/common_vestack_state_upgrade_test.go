@@ -0,0 +1,108 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockUpgradeResource() *schema.Resource {
+	v0 := map[string]*schema.Schema{
+		"name":               {Type: schema.TypeString, Optional: true},
+		"security_group_ids": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"zones":              {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"data_volumes": {Type: schema.TypeSet, Optional: true, Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"volume_name": {Type: schema.TypeString, Optional: true},
+				"size":        {Type: schema.TypeString, Optional: true},
+			},
+		}},
+	}
+	v1 := map[string]*schema.Schema{
+		"instance_name":      {Type: schema.TypeString, Optional: true},
+		"security_group_ids": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"zones":              {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"data_volumes": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {Type: schema.TypeString, Optional: true},
+				"size": {Type: schema.TypeInt, Optional: true},
+			},
+		}},
+	}
+	r := &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error { return nil },
+		Read:   func(d *schema.ResourceData, meta interface{}) error { return nil },
+		Update: func(d *schema.ResourceData, meta interface{}) error { return nil },
+		Delete: func(d *schema.ResourceData, meta interface{}) error { return nil },
+		Schema: v1,
+	}
+	return ResourceStateMigrations(r,
+		StateMigration{
+			Version: 0,
+			Schema:  v0,
+			Upgrade: StateUpgrades(
+				RenameField("name", "instance_name"),
+				ListToSet("zones"),
+				SetToList("data_volumes", func(a, b interface{}) bool {
+					return a.(map[string]interface{})["volume_name"].(string) < b.(map[string]interface{})["volume_name"].(string)
+				}),
+				RenameField("data_volumes.volume_name", "data_volumes.name"),
+				ConvertField("data_volumes.size", func(v interface{}) (interface{}, error) {
+					return strconv.Atoi(v.(string))
+				}),
+			),
+		},
+		StateMigration{
+			Version: 1,
+			Upgrade: ChangeId(func(id string, rawState map[string]interface{}) (string, error) {
+				return fmt.Sprintf("cn-mock:%s", id), nil
+			}),
+		},
+	)
+}
+
+func loadStateFixture(t *testing.T, file string) map[string]interface{} {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state map[string]interface{}
+	if err = json.Unmarshal(b, &state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func Test_StateUpgrade(t *testing.T) {
+	r := mockUpgradeResource()
+	assert.Equal(t, 2, r.SchemaVersion)
+	assert.Nil(t, r.InternalValidate(nil, true))
+
+	state, err := UpgradeState(r, loadStateFixture(t, "testdata/state_upgrade_v0.json"), 0, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, CheckStateSchema(r, state))
+	assert.Equal(t, "cn-mock:i-mock1", state["id"])
+	assert.Equal(t, "instance-1", state["instance_name"])
+	assert.Nil(t, state["name"])
+	assert.Equal(t, []interface{}{"cn-mock-a", "cn-mock-b"}, state["zones"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "vol-a", "size": 20},
+		map[string]interface{}{"name": "vol-b", "size": 40},
+	}, state["data_volumes"])
+
+	// only the migrations after the version of the state
+	state, err = UpgradeState(r, map[string]interface{}{"id": "i-mock2", "instance_name": "instance-2"}, 1, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "cn-mock:i-mock2", state["id"])
+
+	assert.NotNil(t, CheckStateSchema(r, loadStateFixture(t, "testdata/state_upgrade_v0.json")))
+	_, err = UpgradeState(r, map[string]interface{}{"data_volumes": []interface{}{
+		map[string]interface{}{"volume_name": "vol-a", "size": "large"},
+	}}, 0, nil)
+	assert.NotNil(t, err)
+}
@@ -6,16 +6,46 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+const defaultSyncConcurrency = 10
+
+// syncSemaphore shared by clients which are not built by Config.Client
 var syncSemaphore *semaphore.Weighted
 
 func InitSyncLimit() {
-	syncSemaphore = semaphore.NewWeighted(10)
+	syncSemaphore = semaphore.NewWeighted(defaultSyncConcurrency)
 }
 
 func Acquire() {
 	_ = syncSemaphore.Acquire(context.Background(), 1)
 }
 
+// AcquireContext Release must be called only when err is nil
+func AcquireContext(ctx context.Context) error {
+	return syncSemaphore.Acquire(ctx, 1)
+}
+
 func Release() {
 	syncSemaphore.Release(1)
 }
+
+// Acquire limit the sync goroutines of this provider instance and region, the waiting is abandoned when the
+// provider is stopped. Release must be called only when err is nil
+func (c *SdkClient) Acquire() error {
+	return c.AcquireContext(c.StopContext())
+}
+
+// AcquireContext Release must be called only when err is nil
+func (c *SdkClient) AcquireContext(ctx context.Context) error {
+	return c.syncLimit().Acquire(ctx, 1)
+}
+
+func (c *SdkClient) Release() {
+	c.syncLimit().Release(1)
+}
+
+func (c *SdkClient) syncLimit() *semaphore.Weighted {
+	if c == nil || c.syncSemaphore == nil {
+		return syncSemaphore
+	}
+	return c.syncSemaphore
+}
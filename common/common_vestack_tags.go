@@ -67,7 +67,12 @@ var TagsHash = func(v interface{}) int {
 	var (
 		buf bytes.Buffer
 	)
-	buf.WriteString(fmt.Sprintf("%v#%v", m["key"], m["value"]))
+	// the value is missing when it is optional and not set
+	value := m["value"]
+	if value == nil {
+		value = ""
+	}
+	buf.WriteString(fmt.Sprintf("%v#%v", m["key"], value))
 	return hashcode.String(buf.String())
 }
 
@@ -87,59 +92,252 @@ var VkeTagsResponseHash = func(v interface{}) int {
 
 type GetUniversalInfo func(actionName string) UniversalInfo
 
-func SetResourceTags(serviceClient *SdkClient, addAction, RemoveAction, resourceType string,
-	resourceData *schema.ResourceData, getUniversalInfo GetUniversalInfo) []Callback {
-	var callbacks []Callback
-	addedTags, removedTags, _, _ := GetSetDifference("tags", resourceData, TagsHash, false)
+// TagEnabled like ProjectUpdateEnabled, the Dispatcher sets the tags of the resource on create and update,
+// the service only declares the resource type and the tag api flavor
+type TagEnabled interface {
+	TagInfo() *TagInfo
+}
+
+// TagFlavor the actions and parameter format of the tag api of a product
+type TagFlavor struct {
+	AddAction    string
+	RemoveAction string
+	ListAction   string
+	// ResourceIdsField ResourceIds, or ResourceNames of iam
+	ResourceIdsField string
+	// Json ResourceIds, Tags and TagKeys are json arrays, otherwise they are ResourceIds.N, Tags.N.Key and TagKeys.N
+	Json bool
+}
+
+var (
+	// TagFlavorDefault vpc, clb, ebs, natgateway and directconnect
+	TagFlavorDefault = TagFlavor{
+		AddAction:        "TagResources",
+		RemoveAction:     "UntagResources",
+		ListAction:       "ListTagsForResources",
+		ResourceIdsField: "ResourceIds",
+	}
+	TagFlavorEcs = TagFlavor{
+		AddAction:        "CreateTags",
+		RemoveAction:     "DeleteTags",
+		ListAction:       "DescribeTags",
+		ResourceIdsField: "ResourceIds",
+	}
+	TagFlavorIam = TagFlavor{
+		AddAction:        "TagResources",
+		RemoveAction:     "UntagResources",
+		ListAction:       "ListTagsForResources",
+		ResourceIdsField: "ResourceNames",
+	}
+	// TagFlavorJson vke
+	TagFlavorJson = TagFlavor{
+		AddAction:        "TagResources",
+		RemoveAction:     "UntagResources",
+		ListAction:       "ListTagsForResources",
+		ResourceIdsField: "ResourceIds",
+		Json:             true,
+	}
+)
+
+type TagInfo struct {
+	ResourceType  string
+	Flavor        TagFlavor
+	UniversalInfo GetUniversalInfo
+	// CreateWithTags the create call of the resource carries the tags, the Dispatcher only sets tags on update
+	CreateWithTags bool
+}
 
+type Tag struct {
+	Client *SdkClient
+}
+
+func NewTagService(c *SdkClient) *Tag {
+	return &Tag{
+		Client: c,
+	}
+}
+
+// SetTags remove then add the changed tags of resource, the resource id is read when the call is executed
+func (t *Tag) SetTags(info *TagInfo, resourceData *schema.ResourceData, service ResourceService) []Callback {
+	var (
+		addedTags   *schema.Set
+		removedTags *schema.Set
+	)
+	addedTags, removedTags = GetTagsDifference(resourceData)
+	resourceId := func(d *schema.ResourceData) string {
+		if service == nil {
+			return d.Id()
+		}
+		return service.ReadResourceId(d.Id())
+	}
+	flavor := info.Flavor
 	removeCallback := Callback{
 		Call: SdkCall{
-			Action:      RemoveAction,
+			Action:      flavor.RemoveAction,
 			ConvertMode: RequestConvertIgnore,
 			BeforeCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (bool, error) {
-				if removedTags != nil && len(removedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds.1"] = resourceData.Id()
-					(*call.SdkParam)["ResourceType"] = resourceType
-					for index, tag := range removedTags.List() {
-						(*call.SdkParam)["TagKeys."+strconv.Itoa(index+1)] = tag.(map[string]interface{})["key"].(string)
-					}
+				if removedTags == nil || removedTags.Len() == 0 {
+					return false, nil
+				}
+				var keys []string
+				for _, tag := range removedTags.List() {
+					keys = append(keys, tag.(map[string]interface{})["key"].(string))
+				}
+				(*call.SdkParam)["ResourceType"] = info.ResourceType
+				if flavor.Json {
+					(*call.SdkParam)[flavor.ResourceIdsField] = []string{resourceId(d)}
+					(*call.SdkParam)["TagKeys"] = keys
 					return true, nil
 				}
-				return false, nil
+				(*call.SdkParam)[flavor.ResourceIdsField+".1"] = resourceId(d)
+				for index, key := range keys {
+					(*call.SdkParam)["TagKeys."+strconv.Itoa(index+1)] = key
+				}
+				return true, nil
 			},
 			ExecuteCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return serviceClient.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
+				return t.Client.UniversalClient.DoCall(info.UniversalInfo(call.Action), call.SdkParam)
 			},
 		},
 	}
-	callbacks = append(callbacks, removeCallback)
 
 	addCallback := Callback{
 		Call: SdkCall{
-			Action:      addAction,
+			Action:      flavor.AddAction,
 			ConvertMode: RequestConvertIgnore,
 			BeforeCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (bool, error) {
-				if addedTags != nil && len(addedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds.1"] = resourceData.Id()
-					(*call.SdkParam)["ResourceType"] = resourceType
-					for index, tag := range addedTags.List() {
-						(*call.SdkParam)["Tags."+strconv.Itoa(index+1)+".Key"] = tag.(map[string]interface{})["key"].(string)
-						(*call.SdkParam)["Tags."+strconv.Itoa(index+1)+".Value"] = tag.(map[string]interface{})["value"].(string)
+				if addedTags == nil || addedTags.Len() == 0 {
+					return false, nil
+				}
+				(*call.SdkParam)["ResourceType"] = info.ResourceType
+				if flavor.Json {
+					var tags []map[string]interface{}
+					for _, tag := range addedTags.List() {
+						tags = append(tags, map[string]interface{}{
+							"Key":   tag.(map[string]interface{})["key"],
+							"Value": tag.(map[string]interface{})["value"],
+						})
 					}
+					(*call.SdkParam)[flavor.ResourceIdsField] = []string{resourceId(d)}
+					(*call.SdkParam)["Tags"] = tags
 					return true, nil
 				}
-				return false, nil
+				(*call.SdkParam)[flavor.ResourceIdsField+".1"] = resourceId(d)
+				for index, tag := range addedTags.List() {
+					(*call.SdkParam)["Tags."+strconv.Itoa(index+1)+".Key"] = tag.(map[string]interface{})["key"].(string)
+					(*call.SdkParam)["Tags."+strconv.Itoa(index+1)+".Value"] = tag.(map[string]interface{})["value"].(string)
+				}
+				return true, nil
 			},
 			ExecuteCall: func(d *schema.ResourceData, client *SdkClient, call SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return serviceClient.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
+				return t.Client.UniversalClient.DoCall(info.UniversalInfo(call.Action), call.SdkParam)
 			},
 		},
 	}
-	callbacks = append(callbacks, addCallback)
+	return []Callback{removeCallback, addCallback}
+}
+
+// ListTags the tags of resources of the type, all resources of the type when ids is empty.
+// Every tag is a map of ResourceId, Key and Value
+func (t *Tag) ListTags(info *TagInfo, ids []string) ([]interface{}, error) {
+	var (
+		result    []interface{}
+		nextToken string
+	)
+	flavor := info.Flavor
+	for {
+		condition := map[string]interface{}{
+			"ResourceType": info.ResourceType,
+			"MaxResults":   100,
+		}
+		if flavor.Json {
+			if len(ids) > 0 {
+				condition[flavor.ResourceIdsField] = ids
+			}
+		} else {
+			for index, id := range ids {
+				condition[flavor.ResourceIdsField+"."+strconv.Itoa(index+1)] = id
+			}
+		}
+		if nextToken != "" {
+			condition["NextToken"] = nextToken
+		}
+		logger.Debug(logger.ReqFormat, flavor.ListAction, condition)
+		resp, err := t.Client.UniversalClient.DoCall(info.UniversalInfo(flavor.ListAction), &condition)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug(logger.RespFormat, flavor.ListAction, condition, *resp)
+
+		var tags interface{}
+		for _, field := range []string{"Result.ResourceTags", "Result.Tags"} {
+			if tags, _ = ObtainSdkValue(field, *resp); tags != nil {
+				break
+			}
+		}
+		list, _ := tags.([]interface{})
+		for _, v := range list {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tag := map[string]interface{}{
+				"ResourceType": info.ResourceType,
+			}
+			for _, pair := range [][]string{
+				{"ResourceId", "ResourceId"}, {"ResourceId", "ResourceName"},
+				{"Key", "TagKey"}, {"Key", "Key"},
+				{"Value", "TagValue"}, {"Value", "Value"},
+			} {
+				if value, ok := m[pair[1]]; ok {
+					tag[pair[0]] = value
+				}
+			}
+			result = append(result, tag)
+		}
+
+		token, _ := ObtainSdkValue("Result.NextToken", *resp)
+		nextToken, _ = token.(string)
+		if nextToken == "" || len(list) == 0 {
+			return result, nil
+		}
+	}
+}
 
-	return callbacks
+// ResourceTags the Key and Value of tags of one resource, for the products which don't return tags on describe
+func (t *Tag) ResourceTags(info *TagInfo, id string) ([]interface{}, error) {
+	tags, err := t.ListTags(info, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, 0)
+	for _, tag := range tags {
+		m := tag.(map[string]interface{})
+		if resourceId, ok := m["ResourceId"]; ok && resourceId != id {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"Key":   m["Key"],
+			"Value": m["Value"],
+		})
+	}
+	return FilterSystemTags(result), nil
+}
+
+// SetResourceTags
+// Deprecated: implement TagEnabled, the Dispatcher sets the tags
+func SetResourceTags(serviceClient *SdkClient, addAction, RemoveAction, resourceType string,
+	resourceData *schema.ResourceData, getUniversalInfo GetUniversalInfo) []Callback {
+	flavor := TagFlavorDefault
+	flavor.AddAction = addAction
+	flavor.RemoveAction = RemoveAction
+	return NewTagService(serviceClient).SetTags(&TagInfo{
+		ResourceType:  resourceType,
+		Flavor:        flavor,
+		UniversalInfo: getUniversalInfo,
+	}, resourceData, nil)
 }
 
 func FilterSystemTags(tags []interface{}) []interface{} {
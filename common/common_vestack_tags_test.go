@@ -3,6 +3,8 @@ package common
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +36,93 @@ func Test_FilterSystemTags(t *testing.T) {
 		},
 	})
 }
+
+type mockTaggedVpcService struct {
+	mockVpcService
+}
+
+func (s *mockTaggedVpcService) TagInfo() *TagInfo {
+	return &TagInfo{
+		ResourceType:  "vpc",
+		Flavor:        TagFlavorDefault,
+		UniversalInfo: mockVpcUniversalInfo,
+	}
+}
+
+func Test_TagEnabled_Dispatcher(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+	svc := &mockTaggedVpcService{mockVpcService: *vpc}
+
+	server.On("CreateVpc", MockResponse{Result: map[string]interface{}{"VpcId": "vpc-new"}})
+	server.On("DescribeVpcs", MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-new", "Status": "Available"},
+	}}})
+	server.On("TagResources", MockResponse{Result: map[string]interface{}{}}, MockResponse{Result: map[string]interface{}{}})
+	server.On("UntagResources", MockResponse{Result: map[string]interface{}{}})
+
+	r := mockVpcResource()
+	r.Schema["tags"] = TagsSchema()
+	config := map[string]interface{}{
+		"cidr_block": "172.16.0.0/16",
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	assert.Nil(t, DefaultDispatcher().Create(svc, d, r))
+	requests := server.Requests("TagResources")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "vpc", requests[0].Params["ResourceType"])
+	assert.Equal(t, "vpc-new", requests[0].Params["ResourceIds.1"])
+	assert.Equal(t, "env", requests[0].Params["Tags.1.Key"])
+	assert.Equal(t, "dev", requests[0].Params["Tags.1.Value"])
+
+	state := d.State()
+	config["tags"] = []interface{}{
+		map[string]interface{}{"key": "app", "value": "web"},
+	}
+	diff, err := schema.InternalMap(r.Schema).Diff(state, terraform.NewResourceConfigRaw(config), nil, nil, true)
+	assert.Nil(t, err)
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	assert.Nil(t, err)
+	assert.Nil(t, DefaultDispatcher().Update(svc, d, r))
+	requests = server.Requests("UntagResources")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "env", requests[0].Params["TagKeys.1"])
+	requests = server.Requests("TagResources")
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "app", requests[1].Params["Tags.1.Key"])
+}
+
+func Test_Tag_ListTags(t *testing.T) {
+	server, vpc := newMockVpcService(t)
+	defer server.Close()
+
+	server.On("ListTagsForResources",
+		MockResponse{Result: map[string]interface{}{
+			"NextToken": "token-1",
+			"ResourceTags": []interface{}{
+				map[string]interface{}{"ResourceId": "vpc-1", "TagKey": "env", "TagValue": "dev"},
+			},
+		}},
+		MockResponse{Result: map[string]interface{}{
+			"ResourceTags": []interface{}{
+				map[string]interface{}{"ResourceId": "vpc-2", "TagKey": "app", "TagValue": "web"},
+			},
+		}},
+	)
+	info := (&mockTaggedVpcService{}).TagInfo()
+	tags, err := NewTagService(vpc.Client).ListTags(info, []string{"vpc-1", "vpc-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"ResourceType": "vpc", "ResourceId": "vpc-1", "Key": "env", "Value": "dev"},
+		map[string]interface{}{"ResourceType": "vpc", "ResourceId": "vpc-2", "Key": "app", "Value": "web"},
+	}, tags)
+
+	requests := server.Requests("ListTagsForResources")
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "vpc-2", requests[0].Params["ResourceIds.2"])
+	assert.Nil(t, requests[0].Params["NextToken"])
+	assert.Equal(t, "token-1", requests[1].Params["NextToken"])
+}
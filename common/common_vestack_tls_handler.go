@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"strings"
 
 	"github.com/volcengine/volcengine-go-sdk/volcengine/client"
 	"github.com/volcengine/volcengine-go-sdk/volcengine/client/metadata"
@@ -18,17 +17,11 @@ var tlsUnmarshalErrorHandler = request.NamedHandler{Name: "TlsUnmarshalErrorHand
 
 func (u *BypassSvc) NewTlsClient() *client.Client {
 	svc := "TLS"
-	config := u.Session.ClientConfig(svc)
+	config := u.Session.ClientConfig(svc, u.overrides.configs(u.Session, svc)...)
 	var (
 		endpoint string
 	)
-	format := fmt.Sprintf("tls-%s.volces.com", config.SigningRegion)
-
-	if len(u.endpoints) > 0 {
-		if end, ok := u.endpoints[strings.ToLower(svc)]; ok {
-			format = end
-		}
-	}
+	format := u.overrides.bypassHost(svc, fmt.Sprintf("tls-%s.volces.com", config.SigningRegion))
 	format = "%s://" + format
 	if config.Config.DisableSSL != nil && *config.Config.DisableSSL {
 		endpoint = fmt.Sprintf(format, "http")
@@ -27,17 +27,11 @@ var tosUnmarshalErrorHandler = request.NamedHandler{Name: "TosUnmarshalErrorHand
 
 func (u *BypassSvc) NewTosClient(info *BypassSvcInfo) *client.Client {
 	svc := "tos"
-	config := u.Session.ClientConfig(svc)
+	config := u.Session.ClientConfig(svc, u.overrides.configs(u.Session, svc)...)
 	var (
 		endpoint string
 	)
-	format := fmt.Sprintf("tos-%s.volces.com", config.SigningRegion)
-
-	if len(u.endpoints) > 0 {
-		if end, ok := u.endpoints[svc]; ok {
-			format = end
-		}
-	}
+	format := u.overrides.bypassHost(svc, fmt.Sprintf("tos-%s.volces.com", config.SigningRegion))
 	if info.Domain == "" {
 		format = "%s://" + format
 		if config.Config.DisableSSL != nil && *config.Config.DisableSSL {
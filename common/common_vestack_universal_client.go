@@ -1,8 +1,6 @@
 package common
 
 import (
-	"strings"
-
 	"github.com/volcengine/volcengine-go-sdk/volcengine/client"
 	"github.com/volcengine/volcengine-go-sdk/volcengine/client/metadata"
 	"github.com/volcengine/volcengine-go-sdk/volcengine/corehandlers"
@@ -31,7 +29,7 @@ const (
 
 type Universal struct {
 	Session   *session.Session
-	endpoints map[string]string
+	overrides ServiceOverrides
 }
 
 type UniversalInfo struct {
@@ -42,27 +40,21 @@ type UniversalInfo struct {
 	ContentType ContentType
 }
 
-func NewUniversalClient(session *session.Session, endpoints map[string]string) *Universal {
+func NewUniversalClient(session *session.Session, overrides ServiceOverrides) *Universal {
 	return &Universal{
 		Session:   session,
-		endpoints: endpoints,
+		overrides: overrides,
 	}
 }
 
 func (u *Universal) newTargetClient(info UniversalInfo) *client.Client {
-	config := u.Session.ClientConfig(info.ServiceName)
-	endpoint := config.Endpoint
-	if len(u.endpoints) > 0 {
-		if end, ok := u.endpoints[info.ServiceName]; ok {
-			endpoint = endpoint[0:strings.Index(config.Endpoint, "//")] + "//" + end
-		}
-	}
+	config := u.Session.ClientConfig(info.ServiceName, u.overrides.configs(u.Session, info.ServiceName)...)
 	c := client.New(
 		*config.Config,
 		metadata.ClientInfo{
 			SigningName:   config.SigningName,
 			SigningRegion: config.SigningRegion,
-			Endpoint:      endpoint,
+			Endpoint:      config.Endpoint,
 			APIVersion:    info.Version,
 			ServiceName:   info.ServiceName,
 			ServiceID:     info.ServiceName,
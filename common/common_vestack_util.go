@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -172,3 +173,11 @@ func MergeDateSourceToResource(source map[string]*schema.Schema, target *map[str
 		(*target)[k] = n
 	}
 }
+
+// ValidateDuration the value must be a duration string, e.g. `30s` or `5m`
+func ValidateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration like 1s or 500ms, got %q", k, v))
+	}
+	return
+}
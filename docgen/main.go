@@ -148,6 +148,7 @@ var resourceKeys = map[string]string{
 	//"tls":         "TLS",
 	//"cloudfs":     "CLOUDFS",
 	"direct_connect": "DIRECT_CONNECT",
+	"tag":            "TAG",
 }
 
 type Products struct {
@@ -0,0 +1,7 @@
+data "vestack_resources_by_tag" "default" {
+  resource_types = ["vpc:vpc", "vpc:subnet"]
+  tags {
+    key   = "env"
+    value = "dev"
+  }
+}
@@ -0,0 +1,3 @@
+data "vestack_tags" "default" {
+  resource_types = ["vestack_vpc", "vestack_subnet"]
+}
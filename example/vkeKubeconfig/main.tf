@@ -52,4 +52,18 @@ resource "vestack_vke_kubeconfig" "foo" {
   cluster_id     = vestack_vke_cluster.foo.id
   type           = "Private"
   valid_duration = 2
-}
\ No newline at end of file
+}
+resource "vestack_vke_kubeconfig" "public" {
+  cluster_id     = vestack_vke_cluster.foo.id
+  type           = "Public"
+  valid_duration = 720
+  renew_before   = "72h"
+  output_path    = "${path.module}/kubeconfig-vke"
+}
+
+provider "kubernetes" {
+  host                   = vestack_vke_kubeconfig.public.host
+  cluster_ca_certificate = vestack_vke_kubeconfig.public.cluster_ca_certificate
+  client_certificate     = vestack_vke_kubeconfig.public.client_certificate
+  client_key             = vestack_vke_kubeconfig.public.client_key
+}
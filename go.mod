@@ -9,6 +9,9 @@ require (
 	github.com/hashicorp/terraform-plugin-sdk v1.7.0
 	github.com/stretchr/testify v1.7.0
 	github.com/volcengine/volcengine-go-sdk v1.0.75
+	github.com/zclconf/go-cty v1.2.1
+	golang.org/x/net v0.0.0-20191009170851-d66e71096ffb
 	golang.org/x/sync v0.0.0-20190423024810-112230192c58
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
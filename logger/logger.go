@@ -2,19 +2,93 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-const skip = 1
+const skip = 2
 const ReqFormat = "ACTION:%s;REQ:%+v"
 const RespFormat = "ACTION:%s;REQ:%+v;RESP:%+v"
 const ErrFormat = "ACTION:%s;REQ:%+v;ERR:%+v"
 const AllFormat = "ACTION:%s;REQ:%+v;RESP:%+v;ERR:%+v"
 
+type Level int
+
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+func ParseLevel(s string) (Level, bool) {
+	for l := LevelTrace; l <= LevelError; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, true
+		}
+	}
+	return LevelDebug, false
+}
+
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// Fields correlation fields of a log entry, e.g. action, request_id, resource
+type Fields map[string]interface{}
+
+var (
+	level  = LevelTrace
+	format = FormatText
+
+	boundFields     = make(map[uint64]Fields)
+	boundFieldsLock sync.RWMutex
+)
+
+func init() {
+	// terraform filter the output by TF_LOG, these only reduce or reshape it
+	if l, ok := ParseLevel(os.Getenv("VESTACK_LOG_LEVEL")); ok {
+		level = l
+	}
+	if strings.EqualFold(os.Getenv("VESTACK_LOG_FORMAT"), FormatJson) {
+		format = FormatJson
+	}
+}
+
+func SetLevel(l Level) {
+	level = l
+}
+
+// SetFormat text or json
+func SetFormat(f string) {
+	format = f
+}
+
 func GetGID() uint64 {
 	b := make([]byte, 64)
 	b = b[:runtime.Stack(b, false)]
@@ -24,31 +98,124 @@ func GetGID() uint64 {
 	return n
 }
 
+// Bind attach fields to every log of current goroutine until unbind is called
+func Bind(fields Fields) (unbind func()) {
+	gid := GetGID()
+	boundFieldsLock.Lock()
+	defer boundFieldsLock.Unlock()
+	previous := boundFields[gid]
+	merged := Fields{}
+	for k, v := range previous {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	boundFields[gid] = merged
+	return func() {
+		boundFieldsLock.Lock()
+		defer boundFieldsLock.Unlock()
+		if previous == nil {
+			delete(boundFields, gid)
+		} else {
+			boundFields[gid] = previous
+		}
+	}
+}
+
 func Debug(format string, action string, req interface{}, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(skip)
-	start := strings.LastIndex(file, "/")
-	if start != -1 {
-		file = file[start+1:]
+	if !Enabled(LevelDebug) {
+		return
 	}
-	message := fmt.Sprintf("[DEBUG] {(t-%v):%v:%v}", GetGID(), file, line)
-	log.Printf(message+format, action, req, v)
+	args := append([]interface{}{action, Redact(req)}, redactAll(v)...)
+	output(LevelDebug, Fields{"action": action}, fmt.Sprintf(format, args...))
 }
+
 func DebugInfo(format string, info ...interface{}) {
-	_, file, line, _ := runtime.Caller(skip)
-	start := strings.LastIndex(file, "/")
-	if start != -1 {
-		file = file[start+1:]
+	if !Enabled(LevelDebug) {
+		return
 	}
-	message := fmt.Sprintf("[DEBUG] {%v:%v}", file, line)
-	log.Printf(message+format, info...)
+	output(LevelDebug, nil, fmt.Sprintf(format, redactAll(info)...))
 }
 
 func Info(format string, v ...interface{}) {
+	if !Enabled(LevelInfo) {
+		return
+	}
+	output(LevelInfo, nil, fmt.Sprintf(format, redactAll(v)...))
+}
+
+func Warn(format string, v ...interface{}) {
+	if !Enabled(LevelWarn) {
+		return
+	}
+	output(LevelWarn, nil, fmt.Sprintf(format, redactAll(v)...))
+}
+
+func Error(format string, v ...interface{}) {
+	if !Enabled(LevelError) {
+		return
+	}
+	output(LevelError, nil, fmt.Sprintf(format, redactAll(v)...))
+}
+
+// Log message with extra fields, values of sensitive fields are redacted
+func Log(l Level, fields Fields, format string, v ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	output(l, Redact(fields).(Fields), fmt.Sprintf(format, redactAll(v)...))
+}
+
+func Enabled(l Level) bool {
+	return l >= level
+}
+
+func output(l Level, fields Fields, message string) {
 	_, file, line, _ := runtime.Caller(skip)
 	start := strings.LastIndex(file, "/")
 	if start != -1 {
 		file = file[start+1:]
 	}
-	message := fmt.Sprintf("[INFO] {%v:%v}", file, line)
-	log.Printf(message+format, v)
+	gid := GetGID()
+
+	all := Fields{}
+	boundFieldsLock.RLock()
+	for k, v := range boundFields[gid] {
+		all[k] = v
+	}
+	boundFieldsLock.RUnlock()
+	for k, v := range fields {
+		all[k] = v
+	}
+
+	if format == FormatJson {
+		entry := map[string]interface{}{
+			"@level":     strings.ToLower(l.String()),
+			"@timestamp": time.Now().Format(time.RFC3339Nano),
+			"@caller":    fmt.Sprintf("%v:%v", file, line),
+			"@message":   message,
+			"goroutine":  gid,
+		}
+		for k, v := range all {
+			entry[k] = v
+		}
+		bs, err := json.Marshal(entry)
+		if err != nil {
+			bs = []byte(strconv.Quote(message))
+		}
+		log.Printf("[%s] %s", l, bs)
+		return
+	}
+
+	var keys []string
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf strings.Builder
+	for _, k := range keys {
+		buf.WriteString(fmt.Sprintf("%s=%v ", k, all[k]))
+	}
+	log.Printf("[%s] {(t-%v):%v:%v} %s%s", l, gid, file, line, buf.String(), message)
 }
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const Redacted = "******"
+
+// denyKeywords keys contain any of these are always redacted
+var denyKeywords = []string{
+	"password",
+	"passwd",
+	"secret",
+	"sessiontoken",
+	"securitytoken",
+	"accesstoken",
+	"privatekey",
+	"clientkey",
+	"userdata",
+	"authorization",
+	"kubeconfig",
+}
+
+var (
+	// sensitiveKeys exact keys registered from schema Sensitive flags
+	sensitiveKeys     = map[string]bool{"token": true}
+	sensitiveKeysLock sync.RWMutex
+)
+
+// RegisterSensitiveKeys redact values of these keys, e.g. the Sensitive fields of schema
+func RegisterSensitiveKeys(keys ...string) {
+	sensitiveKeysLock.Lock()
+	defer sensitiveKeysLock.Unlock()
+	for _, k := range keys {
+		sensitiveKeys[normalizeKey(k)] = true
+	}
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.Replace(key, "_", "", -1)
+	return strings.Replace(key, "-", "", -1)
+}
+
+// IsSensitiveKey key can be flattened, e.g. Volumes.1.Password
+func IsSensitiveKey(key string) bool {
+	k := normalizeKey(key)
+	for _, keyword := range denyKeywords {
+		if strings.Contains(k, keyword) {
+			return true
+		}
+	}
+	parts := strings.Split(k, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err == nil {
+			continue
+		}
+		sensitiveKeysLock.RLock()
+		sensitive := sensitiveKeys[parts[i]]
+		sensitiveKeysLock.RUnlock()
+		return sensitive
+	}
+	return false
+}
+
+// Mask keep the head and tail of value, e.g. AKLT****1234
+func Mask(value string) string {
+	if len(value) <= 8 {
+		return Redacted
+	}
+	return value[:4] + "****" + value[len(value)-4:]
+}
+
+func redactAll(v []interface{}) []interface{} {
+	result := make([]interface{}, len(v))
+	for i, item := range v {
+		result[i] = Redact(item)
+	}
+	return result
+}
+
+// Redact deep copy maps, slices and structs with the values of sensitive keys replaced,
+// other values are returned as is
+func Redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case error, string, []byte, bool, int, int64, float64, reflect.Type:
+		return v
+	case Fields:
+		return Fields(redactMap(t))
+	case map[string]interface{}:
+		return redactMap(t)
+	case *map[string]interface{}:
+		if t == nil {
+			return v
+		}
+		return redactMap(*t)
+	case map[string]string:
+		result := make(map[string]string, len(t))
+		for k, val := range t {
+			if IsSensitiveKey(k) {
+				val = Redacted
+			}
+			result[k] = val
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(t))
+		for i, item := range t {
+			result[i] = Redact(item)
+		}
+		return result
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || !hasSensitiveField(rv.Type(), 0) {
+		return v
+	}
+	// sdk input struct, e.g. ecs.RunInstancesInput
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return Redacted
+	}
+	var m interface{}
+	if err = json.Unmarshal(bs, &m); err != nil {
+		return Redacted
+	}
+	return Redact(m)
+}
+
+func redactMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, val := range m {
+		if IsSensitiveKey(k) {
+			result[k] = Redacted
+		} else {
+			result[k] = Redact(val)
+		}
+	}
+	return result
+}
+
+func hasSensitiveField(t reflect.Type, depth int) bool {
+	if depth > 5 {
+		return false
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if IsSensitiveKey(f.Name) || hasSensitiveField(f.Type, depth+1) {
+			return true
+		}
+	}
+	return false
+}
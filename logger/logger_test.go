@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInput struct {
+	InstanceName *string
+	Password     *string
+}
+
+func Test_Redact(t *testing.T) {
+	RegisterSensitiveKeys("mock_sensitive_field")
+	name := "foo"
+	password := "p@ssw0rd"
+	req := &map[string]interface{}{
+		"InstanceName":         "foo",
+		"Password":             "p@ssw0rd",
+		"UserData":             "ZWNobyBoZWxsbw==",
+		"NextToken":            "next",
+		"Volumes.1.Password":   "p@ssw0rd",
+		"MockSensitiveField":   "mock",
+		"NetworkInterfaces.1":  map[string]interface{}{"SecretKey": "sk"},
+		"SecurityGroupIds.1":   []interface{}{"sg-1"},
+		"mock_sensitive_field": "mock",
+	}
+	redacted := Redact(req).(map[string]interface{})
+	assert.Equal(t, "foo", redacted["InstanceName"])
+	assert.Equal(t, "next", redacted["NextToken"])
+	assert.Equal(t, Redacted, redacted["Password"])
+	assert.Equal(t, Redacted, redacted["UserData"])
+	assert.Equal(t, Redacted, redacted["Volumes.1.Password"])
+	assert.Equal(t, Redacted, redacted["MockSensitiveField"])
+	assert.Equal(t, Redacted, redacted["mock_sensitive_field"])
+	assert.Equal(t, Redacted, redacted["NetworkInterfaces.1"].(map[string]interface{})["SecretKey"])
+	// origin is not changed
+	assert.Equal(t, "p@ssw0rd", (*req)["Password"])
+
+	input := Redact(&mockInput{InstanceName: &name, Password: &password}).(map[string]interface{})
+	assert.Equal(t, "foo", input["InstanceName"])
+	assert.Equal(t, Redacted, input["Password"])
+
+	assert.Equal(t, "AKLT****1234", Mask("AKLTabcdefgh1234"))
+	assert.Equal(t, Redacted, Mask("short"))
+}
+
+func Test_Output(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	unbind := Bind(Fields{"resource": "ecs_instance", "resource_id": "i-1"})
+	Debug(ReqFormat, "RunInstances", map[string]interface{}{"Password": "p@ssw0rd"})
+	assert.True(t, strings.Contains(buf.String(), "[DEBUG]"))
+	assert.True(t, strings.Contains(buf.String(), "resource_id=i-1"))
+	assert.False(t, strings.Contains(buf.String(), "p@ssw0rd"))
+
+	buf.Reset()
+	SetFormat(FormatJson)
+	defer SetFormat(FormatText)
+	Log(LevelWarn, Fields{"request_id": "req-1"}, "api request failed")
+	line := buf.String()
+	assert.True(t, strings.Contains(line, "[WARN] "))
+	var entry map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(strings.TrimSpace(line[strings.Index(line, "{"):])), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "i-1", entry["resource_id"])
+	assert.Equal(t, "api request failed", entry["@message"])
+
+	unbind()
+	buf.Reset()
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelTrace)
+	DebugInfo("hidden")
+	assert.Equal(t, "", buf.String())
+}
@@ -149,6 +149,21 @@ func AccTestCheckResourceRemove(acc *AccTestResource) resource.TestCheckFunc {
 }
 
 func AccTestPreCheck(t *testing.T) {
+	// replay mode do not need real credentials, requests are served by cassette
+	if os.Getenv("VESTACK_CASSETTE_MODE") == common.CassetteModeReplay {
+		if os.Getenv("VESTACK_CASSETTE_FILE") == "" {
+			t.Fatal("VESTACK_CASSETTE_FILE must be set in replay mode")
+		}
+		for _, k := range []string{"VESTACK_ACCESS_KEY", "VESTACK_SECRET_KEY"} {
+			if v := os.Getenv(k); v == "" {
+				os.Setenv(k, "replay")
+			}
+		}
+		if v := os.Getenv("VESTACK_REGION"); v == "" {
+			os.Setenv("VESTACK_REGION", "cn-beijing")
+		}
+		return
+	}
 	if v := os.Getenv("VOLCENGINE_ACCESS_KEY"); v == "" {
 		t.Fatal("VOLCENGINE_ACCESS_KEY must be set for acceptance tests")
 	}
@@ -156,10 +156,6 @@ func (s *VestackCertificateService) ModifyResource(resourceData *schema.Resource
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "Certificate", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -241,3 +237,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackCertificateService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "Certificate",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
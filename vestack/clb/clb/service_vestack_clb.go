@@ -503,10 +503,6 @@ func (s *VestackClbService) ModifyResource(resourceData *schema.ResourceData, re
 		callbacks = append(callbacks, renewCallback)
 	}
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "CLB", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -689,3 +685,12 @@ func (s *VestackClbService) UnsubscribeInfo(resourceData *schema.ResourceData, r
 	}
 	return &info, nil
 }
+
+func (s *VestackClbService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "CLB",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
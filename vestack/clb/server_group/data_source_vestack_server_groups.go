@@ -88,6 +88,7 @@ func DataSourceVestackServerGroups() *schema.Resource {
 							Computed:    true,
 							Description: "The address ip version of the ServerGroup.",
 						},
+						"tags": ve.TagsSchemaComputed(),
 					},
 				},
 			},
@@ -66,6 +66,7 @@ func ResourceVestackServerGroup() *schema.Resource {
 				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
 				Description:  "The address ip version of the ServerGroup. Valid values: `ipv4`, `ipv6`. Default is `ipv4`.",
 			},
+			"tags": ve.TagsSchema(),
 		},
 	}
 }
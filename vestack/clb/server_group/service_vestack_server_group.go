@@ -116,6 +116,9 @@ func (s *VestackServerGroupService) CreateResource(resourceData *schema.Resource
 			Action:      "CreateServerGroup",
 			ConvertMode: ve.RequestConvertAll,
 			Convert: map[string]ve.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
 				"servers": {
 					ConvertType: ve.ConvertListN,
 				},
@@ -159,6 +162,11 @@ func (s *VestackServerGroupService) ModifyResource(resourceData *schema.Resource
 		Call: ve.SdkCall{
 			Action:      "ModifyServerGroupAttributes",
 			ConvertMode: ve.RequestConvertAll,
+			Convert: map[string]ve.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			BeforeCall: func(d *schema.ResourceData, client *ve.SdkClient, call ve.SdkCall) (bool, error) {
 				(*call.SdkParam)["ServerGroupId"] = d.Id()
 				return true, nil
@@ -243,6 +251,7 @@ func (s *VestackServerGroupService) DatasourceResources(*schema.ResourceData, *s
 				TargetField: "ServerGroupIds",
 				ConvertType: ve.ConvertWithN,
 			},
+			"tags": ve.TagFilterConvert(),
 		},
 		NameField:    "ServerGroupName",
 		IdField:      "ServerGroupId",
@@ -289,3 +298,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackServerGroupService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "ServerGroup",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -21,13 +21,11 @@ $ terraform import vestack_server_group_server.default rsp-274xltv2*****8tlv3q3s
 
 func ResourceVestackServerGroupServer() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVestackServerGroupServerCreate,
-		Read:   resourceVestackServerGroupServerRead,
-		Update: resourceVestackServerGroupServerUpdate,
-		Delete: resourceVestackServerGroupServerDelete,
-		Importer: &schema.ResourceImporter{
-			State: serverGroupServerImporter,
-		},
+		Create:   resourceVestackServerGroupServerCreate,
+		Read:     resourceVestackServerGroupServerRead,
+		Update:   resourceVestackServerGroupServerUpdate,
+		Delete:   resourceVestackServerGroupServerDelete,
+		Importer: serverGroupServerCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(1 * time.Hour),
 			Update: schema.DefaultTimeout(1 * time.Hour),
@@ -100,7 +100,10 @@ func (s *VestackServerGroupServerService) ReadResource(resourceData *schema.Reso
 	if serverGroupServerId == "" {
 		serverGroupServerId = resourceData.Id()
 	}
-	ids := strings.Split(serverGroupServerId, ":")
+	ids, err := serverGroupServerCompositeId.Decode(serverGroupServerId)
+	if err != nil {
+		return data, err
+	}
 	req := map[string]interface{}{
 		"ServerGroupId": ids[0],
 		"ServerIds.1":   ids[1],
@@ -171,7 +174,11 @@ func (s *VestackServerGroupServerService) CreateResource(resourceData *schema.Re
 			AfterCall: func(d *schema.ResourceData, client *ve.SdkClient, resp *map[string]interface{}, call ve.SdkCall) error {
 				// 注意 获取内容 这个地方不能是指针 需要转一次
 				id, _ := ve.ObtainSdkValue("Result.ServerIds.0", *resp)
-				d.SetId(fmt.Sprintf("%s:%s", (*call.SdkParam)["ServerGroupId"], id.(string)))
+				compositeId, err := serverGroupServerCompositeId.Encode(fmt.Sprint((*call.SdkParam)["ServerGroupId"]), id.(string))
+				if err != nil {
+					return err
+				}
+				d.SetId(compositeId)
 				return nil
 			},
 			ExtraRefresh: map[ve.ResourceService]*ve.StateRefresh{
@@ -107,6 +107,7 @@ func ResourceVestackDirectConnectConnection() *schema.Resource {
 				Required:    true,
 				Description: "The dedicated line contact email.",
 			},
+			"tags": ve.TagsSchema(),
 		},
 	}
 	return resource
@@ -129,7 +129,11 @@ func (s *VestackDirectConnectConnectionService) CreateResource(resourceData *sch
             // 修改action
             Action:      "OpsCreateDirectConnectConnection",
             ConvertMode: ve.RequestConvertAll,
-            Convert:     map[string]ve.RequestConvert{},
+            Convert: map[string]ve.RequestConvert{
+                "tags": {
+                    Ignore: true,
+                },
+            },
             ExecuteCall: func(d *schema.ResourceData, client *ve.SdkClient, call ve.SdkCall) (*map[string]interface{}, error) {
                 logger.Debug(logger.RespFormat, call.Action, call.SdkParam)
             //使用universal client
@@ -164,7 +168,11 @@ func (s *VestackDirectConnectConnectionService) ModifyResource(resourceData *sch
 		Call: ve.SdkCall{
 			Action:      "ModifyDirectConnectConnectionAttributes",
 			ConvertMode: ve.RequestConvertAll,
-			Convert:     map[string]ve.RequestConvert{},
+			Convert: map[string]ve.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			BeforeCall: func(d *schema.ResourceData, client *ve.SdkClient, call ve.SdkCall) (bool, error) {
 				(*call.SdkParam)["DirectConnectConnectionId"] = d.Id()
 				return true, nil
@@ -247,3 +255,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackDirectConnectConnectionService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "dxc",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
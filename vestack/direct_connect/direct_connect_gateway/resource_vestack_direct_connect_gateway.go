@@ -16,6 +16,10 @@ DirectConnectGateway can be imported using the id, e.g.
 $ terraform import vestack_direct_connect_gateway.default resource_id
 ```
 
+Notice
+The `tags` is a set since schema version 1. The state of schema version 0, which `tags` is a list, is upgraded
+automatically. The `value` of a tag is optional and defaults to "".
+
 */
 
 func ResourceVestackDirectConnectGateway() *schema.Resource {
@@ -43,28 +47,56 @@ func ResourceVestackDirectConnectGateway() *schema.Resource {
 				Optional:    true,
 				Description: "The description of direct connect gateway.",
 			},
+			"tags": directConnectGatewayTagsSchema(),
+		},
+	}
+	return ve.ResourceStateMigrations(resource, ve.StateMigration{
+		Version: 0,
+		Schema:  resourceVestackDirectConnectGatewayV0().Schema,
+		Upgrade: ve.ListToSet("tags"),
+	})
+}
+
+// directConnectGatewayTagsSchema the value of a tag is optional as the one of schema version 0
+func directConnectGatewayTagsSchema() *schema.Schema {
+	tags := ve.TagsSchema()
+	value := tags.Elem.(*schema.Resource).Schema["value"]
+	value.Required = false
+	value.Optional = true
+	value.Default = ""
+	return tags
+}
+
+// resourceVestackDirectConnectGatewayV0 the tags of schema version 0 is a list, and the value of a tag is optional
+func resourceVestackDirectConnectGatewayV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"direct_connect_gateway_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"tags": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Description: "The direct connect gateway tags.",
+				Type:     schema.TypeList,
+				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"key": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The tag key.",
+							Type:     schema.TypeString,
+							Required: true,
 						},
 						"value": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "The tag value.",
+							Type:     schema.TypeString,
+							Optional: true,
 						},
 					},
 				},
 			},
 		},
 	}
-	return resource
 }
 
 func resourceVestackDirectConnectGatewayCreate(d *schema.ResourceData, meta interface{}) (err error) {
@@ -0,0 +1,64 @@
+package direct_connect_gateway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	ve "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func Test_DirectConnectGatewayStateUpgrade(t *testing.T) {
+	r := ResourceVestackDirectConnectGateway()
+	assert.Equal(t, 1, r.SchemaVersion)
+
+	rawState := map[string]interface{}{
+		"id":                          "dcg-1",
+		"direct_connect_gateway_name": "gateway",
+		"tags": []interface{}{
+			map[string]interface{}{"key": "k1", "value": "v1"},
+			map[string]interface{}{"key": "k2", "value": ""},
+			map[string]interface{}{"key": "k1", "value": "v1"},
+		},
+	}
+	state, err := ve.UpgradeState(r, rawState, 0, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"key": "k1", "value": "v1"},
+			map[string]interface{}{"key": "k2", "value": ""},
+		}, state["tags"])
+		assert.Nil(t, ve.CheckStateSchema(r, state))
+	}
+}
+
+func Test_DirectConnectGatewayTagsWithoutValue(t *testing.T) {
+	r := ResourceVestackDirectConnectGateway()
+	assert.Nil(t, r.InternalValidate(nil, true))
+
+	cfg := map[string]interface{}{
+		"tags": []interface{}{
+			map[string]interface{}{"key": "k1"},
+			map[string]interface{}{"key": "k2", "value": "v2"},
+		},
+	}
+	_, errs := r.Validate(terraform.NewResourceConfigRaw(cfg))
+	assert.Empty(t, errs)
+
+	state := &terraform.InstanceState{
+		ID: "dcg-1",
+		Attributes: map[string]string{
+			"id":     "dcg-1",
+			"tags.#": "2",
+		},
+	}
+	for _, tag := range []map[string]interface{}{{"key": "k1", "value": ""}, {"key": "k2", "value": "v2"}} {
+		hash := ve.TagsHash(tag)
+		state.Attributes[fmt.Sprintf("tags.%d.key", hash)] = tag["key"].(string)
+		state.Attributes[fmt.Sprintf("tags.%d.value", hash)] = tag["value"].(string)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(cfg), nil)
+	if assert.Nil(t, err) && diff != nil {
+		assert.Empty(t, diff.Attributes)
+	}
+}
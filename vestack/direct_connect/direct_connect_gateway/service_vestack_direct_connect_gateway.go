@@ -166,7 +166,11 @@ func (s *VestackDirectConnectGatewayService) ModifyResource(resourceData *schema
 		Call: ve.SdkCall{
 			Action:      "ModifyDirectConnectGatewayAttributes",
 			ConvertMode: ve.RequestConvertAll,
-			Convert:     map[string]ve.RequestConvert{},
+			Convert: map[string]ve.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			BeforeCall: func(d *schema.ResourceData, client *ve.SdkClient, call ve.SdkCall) (bool, error) {
 				(*call.SdkParam)["DirectConnectGatewayId"] = d.Id()
 				return true, nil
@@ -249,3 +253,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackDirectConnectGatewayService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "dxg",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
@@ -154,6 +154,7 @@ func DataSourceVestackVolumes() *schema.Resource {
 							Type:     schema.TypeBool,
 							Computed: true,
 						},
+						"tags": bp.TagsSchemaComputed(),
 					},
 				}},
 		},
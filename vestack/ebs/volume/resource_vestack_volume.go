@@ -118,6 +118,7 @@ func ResourceVestackVolume() *schema.Resource {
 				Optional:    true,
 				Description: "The ProjectName of the Volume.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 }
@@ -159,6 +159,11 @@ func (s *VestackVolumeService) CreateResource(resourceData *schema.ResourceData,
 		Call: bp.SdkCall{
 			Action:      "CreateVolume",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.RespFormat, call.Action, call.SdkParam)
 				return s.Client.EbsClient.CreateVolumeCommon(call.SdkParam)
@@ -359,6 +364,7 @@ func (s *VestackVolumeService) DatasourceResources(*schema.ResourceData, *schema
 				TargetField: "VolumeIds",
 				ConvertType: bp.ConvertWithN,
 			},
+			"tags": bp.TagFilterConvert(),
 		},
 		NameField:    "VolumeName",
 		IdField:      "VolumeId",
@@ -411,3 +417,12 @@ func (s *VestackVolumeService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackVolumeService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "volume",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -20,13 +20,11 @@ $ terraform import vestack_volume_attach.default vol-abc12345:i-abc12345
 
 func ResourceVestackVolumeAttach() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVestackVolumeAttachCreate,
-		Read:   resourceVestackVolumeAttachRead,
-		Update: resourceVestackVolumeAttachUpdate,
-		Delete: resourceVestackVolumeAttachDelete,
-		Importer: &schema.ResourceImporter{
-			State: importVolumeAttach,
-		},
+		Create:   resourceVestackVolumeAttachCreate,
+		Read:     resourceVestackVolumeAttachRead,
+		Update:   resourceVestackVolumeAttachUpdate,
+		Delete:   resourceVestackVolumeAttachDelete,
+		Importer: volumeAttachCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(2 * time.Minute),
 			Delete: schema.DefaultTimeout(2 * time.Minute),
@@ -72,7 +72,10 @@ func (s *VestackVolumeAttachService) ReadResource(resourceData *schema.ResourceD
 		volumeAttachId = s.ReadResourceId(resourceData.Id())
 	}
 
-	parts := strings.Split(volumeAttachId, ":")
+	parts, err := volumeAttachCompositeId.Decode(volumeAttachId)
+	if err != nil {
+		return data, err
+	}
 	req := map[string]interface{}{
 		"VolumeIds.1": parts[0],
 	}
@@ -166,7 +169,11 @@ func (s *VestackVolumeAttachService) CreateResource(resourceData *schema.Resourc
 				return s.Client.EbsClient.AttachVolumeCommon(call.SdkParam)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId(fmt.Sprint((*call.SdkParam)["VolumeId"], ":", (*call.SdkParam)["InstanceId"]))
+				id, err := volumeAttachCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 			ExtraRefresh: map[bp.ResourceService]*bp.StateRefresh{
@@ -232,23 +239,7 @@ func (s *VestackVolumeAttachService) DatasourceResources(*schema.ResourceData, *
 	return bp.DataSourceInfo{}
 }
 
-func importVolumeAttach(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	var err error
-	items := strings.Split(d.Id(), ":")
-	if len(items) != 2 {
-		return []*schema.ResourceData{d}, fmt.Errorf("import id must be of the form VolumeId:instanceId")
-	}
-	err = d.Set("volume_id", items[0])
-	if err != nil {
-		return []*schema.ResourceData{d}, err
-	}
-	err = d.Set("instance_id", items[1])
-	if err != nil {
-		return []*schema.ResourceData{d}, err
-	}
-
-	return []*schema.ResourceData{d}, nil
-}
+var volumeAttachCompositeId = bp.NewCompositeId("volume_id", "instance_id")
 
 func (s *VestackVolumeAttachService) ReadResourceId(id string) string {
 	return id
@@ -2,7 +2,6 @@ package ecs_deployment_set_associate
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -19,26 +18,14 @@ $ terraform import vestack_ecs_deployment_set_associate.default dps-ybti5tkpkv2u
 
 */
 
+var deploymentSetAssociateCompositeId = bp.NewCompositeId("deployment_set_id", "instance_id")
+
 func ResourceVestackEcsDeploymentSetAssociate() *schema.Resource {
 	resource := &schema.Resource{
-		Create: resourceVestackEcsDeploymentSetAssociateCreate,
-		Read:   resourceVestackEcsDeploymentSetAssociateRead,
-		Delete: resourceVestackEcsDeploymentSetAssociateDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
-				items := strings.Split(data.Id(), ":")
-				if len(items) != 2 {
-					return []*schema.ResourceData{data}, fmt.Errorf("import id must split with ':'")
-				}
-				if err := data.Set("deployment_set_id", items[0]); err != nil {
-					return []*schema.ResourceData{data}, err
-				}
-				if err := data.Set("instance_id", items[1]); err != nil {
-					return []*schema.ResourceData{data}, err
-				}
-				return []*schema.ResourceData{data}, nil
-			},
-		},
+		Create:   resourceVestackEcsDeploymentSetAssociateCreate,
+		Read:     resourceVestackEcsDeploymentSetAssociateRead,
+		Delete:   resourceVestackEcsDeploymentSetAssociateDelete,
+		Importer: deploymentSetAssociateCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
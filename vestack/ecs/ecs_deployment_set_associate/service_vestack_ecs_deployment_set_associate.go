@@ -46,7 +46,10 @@ func (s *VestackEcsDeploymentSetAssociateService) ReadResource(resourceData *sch
 		tmpId = s.ReadResourceId(resourceData.Id())
 	}
 
-	ids = strings.Split(tmpId, ":")
+	ids, err = deploymentSetAssociateCompositeId.Decode(tmpId)
+	if err != nil {
+		return data, err
+	}
 	deploymentSetId = ids[0]
 	targetInstanceId = ids[1]
 
@@ -134,7 +137,11 @@ func (s *VestackEcsDeploymentSetAssociateService) CreateResource(resourceData *s
 				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId((*call.SdkParam)["DeploymentSetId"].(string) + ":" + (*call.SdkParam)["InstanceId"].(string))
+				id, err := deploymentSetAssociateCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 			Refresh: &bp.StateRefresh{
@@ -150,7 +150,7 @@ func ResourceVestackEcsInstance() *schema.Resource {
 				Optional:         true,
 				Default:          12,
 				DiffSuppressFunc: EcsInstanceImportDiffSuppress,
-				Description:      "The period of ECS instance.Only effective when instance_charge_type is PrePaid. Default is 12. Unit is Month.",
+				Description:      "The period of ECS instance.Only effective when instance_charge_type is PrePaid. Valid values: 1~36. Default is 12. Unit is Month.",
 			},
 			//"period_unit": {
 			//	Type:     schema.TypeString,
@@ -448,7 +448,7 @@ func ResourceVestackEcsInstance() *schema.Resource {
 	delete(dataSource, "network_interfaces")
 	delete(dataSource, "volumes")
 	bp.MergeDateSourceToResource(dataSource, &resource.Schema)
-	return resource
+	return bp.ResourceDiffConstraints(resource, &VestackEcsService{})
 }
 
 func resourceVestackEcsInstanceCreate(d *schema.ResourceData, meta interface{}) (err error) {
@@ -490,9 +490,7 @@ func resourceVestackEcsInstanceDelete(d *schema.ResourceData, meta interface{})
 func resourceVestackEcsInstanceExist(d *schema.ResourceData, meta interface{}) (flag bool, err error) {
 	err = resourceVestackEcsInstanceRead(d, meta)
 	if err != nil {
-		if strings.Contains(err.Error(), "notfound") || strings.Contains(err.Error(), "not found") ||
-			strings.Contains(err.Error(), "not exist") || strings.Contains(err.Error(), "not associate") ||
-			strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "not_found") {
+		if bp.ResourceNotFoundError(err) {
 			return false, nil
 		}
 		return false, err
@@ -19,34 +19,22 @@ import (
 	"github.com/volcengine/terraform-provider-vestack/logger"
 	"github.com/volcengine/terraform-provider-vestack/vestack/ecs/ecs_deployment_set_associate"
 	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/subnet"
-	"golang.org/x/sync/semaphore"
-	"golang.org/x/time/rate"
 )
 
 var rateInfo *bp.RateInfo
 
+const (
+	// ecsBatchSize the max ids and page size of a describe request
+	ecsBatchSize = 100
+)
+
 func init() {
 	rateInfo = &bp.RateInfo{
-		Create: &bp.Rate{
-			Limiter:   rate.NewLimiter(4, 10),
-			Semaphore: semaphore.NewWeighted(14),
-		},
-		Update: &bp.Rate{
-			Limiter:   rate.NewLimiter(4, 10),
-			Semaphore: semaphore.NewWeighted(14),
-		},
-		Read: &bp.Rate{
-			Limiter:   rate.NewLimiter(4, 10),
-			Semaphore: semaphore.NewWeighted(14),
-		},
-		Delete: &bp.Rate{
-			Limiter:   rate.NewLimiter(4, 10),
-			Semaphore: semaphore.NewWeighted(14),
-		},
-		Data: &bp.Rate{
-			Limiter:   rate.NewLimiter(4, 10),
-			Semaphore: semaphore.NewWeighted(14),
-		},
+		Create: bp.NewRate(4, 10, 14),
+		Update: bp.NewRate(4, 10, 14),
+		Read:   bp.NewRate(4, 10, 14),
+		Delete: bp.NewRate(4, 10, 14),
+		Data:   bp.NewRate(4, 10, 14),
 	}
 }
 
@@ -68,13 +56,10 @@ func (s *VestackEcsService) GetClient() *bp.SdkClient {
 
 func (s *VestackEcsService) ReadResources(condition map[string]interface{}) (data []interface{}, err error) {
 	var (
-		resp               *map[string]interface{}
-		results            interface{}
-		next               string
-		ok                 bool
-		ecsInstance        map[string]interface{}
-		networkInterfaces  []interface{}
-		networkInterfaceId string
+		resp    *map[string]interface{}
+		results interface{}
+		next    string
+		ok      bool
 	)
 	data, err = bp.WithNextTokenQuery(condition, "MaxResults", "NextToken", 20, nil, func(m map[string]interface{}) ([]interface{}, string, error) {
 		ecs := s.Client.EcsClient
@@ -117,46 +102,11 @@ func (s *VestackEcsService) ReadResources(condition map[string]interface{}) (dat
 		return nil, err
 	}
 
-	for _, v := range data {
-		if ecsInstance, ok = v.(map[string]interface{}); !ok {
-			return data, errors.New("Value is not map ")
-		} else {
-			// query primary network interface info of the ecs instance
-			if networkInterfaces, ok = ecsInstance["NetworkInterfaces"].([]interface{}); !ok {
-				return data, errors.New("Instances.NetworkInterfaces is not Slice")
-			}
-			for _, networkInterface := range networkInterfaces {
-				if networkInterfaceMap, ok := networkInterface.(map[string]interface{}); ok &&
-					networkInterfaceMap["Type"] == "primary" {
-					networkInterfaceId = networkInterfaceMap["NetworkInterfaceId"].(string)
-				}
-			}
-
-			action := "DescribeNetworkInterfaces"
-			req := map[string]interface{}{
-				"NetworkInterfaceIds.1": networkInterfaceId,
-			}
-			logger.Debug(logger.ReqFormat, action, req)
-			res, err := s.Client.UniversalClient.DoCall(getVpcUniversalInfo(action), &req)
-			if err != nil {
-				logger.Info("DescribeNetworkInterfaces error:", err)
-				continue
-			}
-			logger.Debug(logger.RespFormat, action, condition, *res)
-
-			networkInterfaceInfos, err := bp.ObtainSdkValue("Result.NetworkInterfaceSets", *res)
-			if err != nil {
-				logger.Info("ObtainSdkValue Result.NetworkInterfaceSets error:", err)
-				continue
-			}
-			if ipv6Sets, ok := networkInterfaceInfos.([]interface{})[0].(map[string]interface{})["IPv6Sets"].([]interface{}); ok {
-				ecsInstance["Ipv6Addresses"] = ipv6Sets
-				ecsInstance["Ipv6AddressCount"] = len(ipv6Sets)
-			}
-		}
+	// query primary network interface info of the ecs instances
+	if err = s.readIpv6Addresses(data); err != nil {
+		logger.Warn("read ipv6 addresses of ecs instances partially failed: %s", err)
 	}
-
-	return data, err
+	return data, nil
 }
 
 func (s *VestackEcsService) ReadResource(resourceData *schema.ResourceData, instanceId string) (data map[string]interface{}, err error) {
@@ -170,7 +120,7 @@ func (s *VestackEcsService) ReadResource(resourceData *schema.ResourceData, inst
 	req := map[string]interface{}{
 		"InstanceIds.1": instanceId,
 	}
-	results, err = s.ReadResources(req)
+	results, err = bp.CachedReadResources(s, req)
 	if err != nil {
 		return data, err
 	}
@@ -304,10 +254,12 @@ func (s *VestackEcsService) WithResourceResponseHandlers(ecs map[string]interfac
 				if _err := recover(); _err != nil {
 					logger.Debug(logger.ReqFormat, "DescribeUserData", _err)
 				}
-				bp.Release()
 				wg.Done()
 			}()
-			bp.Acquire()
+			if userDataErr = s.Client.Acquire(); userDataErr != nil {
+				return
+			}
+			defer s.Client.Release()
 			var (
 				userDataParam *map[string]interface{}
 				userDataResp  *map[string]interface{}
@@ -332,10 +284,12 @@ func (s *VestackEcsService) WithResourceResponseHandlers(ecs map[string]interfac
 				if _err := recover(); _err != nil {
 					logger.Debug(logger.ReqFormat, "DescribeNetworkInterfaces", _err)
 				}
-				bp.Release()
 				wg.Done()
 			}()
-			bp.Acquire()
+			if networkInterfaceErr = s.Client.Acquire(); networkInterfaceErr != nil {
+				return
+			}
+			defer s.Client.Release()
 			var (
 				networkInterfaceParam *map[string]interface{}
 				networkInterfaceResp  *map[string]interface{}
@@ -1143,10 +1097,6 @@ func (s *VestackEcsService) ModifyResource(resourceData *schema.ResourceData, re
 	startInstance := s.StartOrStopInstanceCallback(resourceData, false, &flag)
 	callbacks = append(callbacks, startInstance)
 
-	// 更新Tags
-	setResourceTagsCallbacks := bp.SetResourceTags(s.Client, "CreateTags", "DeleteTags", "instance", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -1251,13 +1201,13 @@ func (s *VestackEcsService) DatasourceResources(data *schema.ResourceData, resou
 		ExtraData: func(sourceData []interface{}) (extraData []interface{}, err error) {
 			sourceData, err = s.readInstanceTypes(sourceData)
 			if err != nil {
-				return extraData, err
+				return nil, fmt.Errorf("read instance types of ecs instances error: %w", err)
 			}
 			sourceData, err = s.readEbsVolumes(sourceData)
 			if err != nil {
-				return extraData, err
+				return nil, fmt.Errorf("read volumes of ecs instances error: %w", err)
 			}
-			return sourceData, err
+			return sourceData, nil
 		},
 	}
 }
@@ -1452,81 +1402,123 @@ func (s *VestackEcsService) ReadResourceId(id string) string {
 	return id
 }
 
-func (s *VestackEcsService) readInstanceTypes(sourceData []interface{}) (extraData []interface{}, err error) {
-	//merge instance_type_info
-	var (
-		wg      sync.WaitGroup
-		syncMap sync.Map
-	)
-	if len(sourceData) == 0 {
-		return sourceData, err
-	}
-	wg.Add(len(sourceData))
-	for _, data := range sourceData {
-		instance := data
-		var (
-			instanceTypeId interface{}
-			action         string
-			resp           *map[string]interface{}
-			results        interface{}
-			_err           error
-		)
-		go func() {
-			defer func() {
-				if e := recover(); e != nil {
-					logger.Debug(logger.ReqFormat, action, e)
+// readIpv6Addresses fill Ipv6Addresses of the primary network interfaces of instances,
+// the network interfaces are described in batches and the errors of failed batches are returned
+func (s *VestackEcsService) readIpv6Addresses(instances []interface{}) error {
+	var ids []string
+	primary := make(map[string]map[string]interface{})
+	for _, v := range instances {
+		instance, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		networkInterfaces, _ := instance["NetworkInterfaces"].([]interface{})
+		for _, networkInterface := range networkInterfaces {
+			if networkInterfaceMap, ok := networkInterface.(map[string]interface{}); ok &&
+				networkInterfaceMap["Type"] == "primary" {
+				if id, ok := networkInterfaceMap["NetworkInterfaceId"].(string); ok {
+					ids = append(ids, id)
+					primary[id] = instance
 				}
-				bp.Release()
-				wg.Done()
-			}()
-			bp.Acquire()
-
-			instanceTypeId, _err = bp.ObtainSdkValue("InstanceTypeId", instance)
-			if _err != nil {
-				syncMap.Store(instanceTypeId, err)
-				return
 			}
-			//if exist continue
-			if _, ok := syncMap.Load(instanceTypeId); ok {
-				return
+		}
+	}
+	return bp.WithBatchQuery(ids, ecsBatchSize, func(batch []string) error {
+		action := "DescribeNetworkInterfaces"
+		req := map[string]interface{}{
+			"PageSize": ecsBatchSize,
+		}
+		for i, id := range batch {
+			req[fmt.Sprintf("NetworkInterfaceIds.%d", i+1)] = id
+		}
+		logger.Debug(logger.ReqFormat, action, req)
+		resp, err := s.Client.UniversalClient.DoCall(getVpcUniversalInfo(action), &req)
+		if err != nil {
+			return err
+		}
+		logger.Debug(logger.RespFormat, action, req, *resp)
+		results, err := bp.ObtainSdkValue("Result.NetworkInterfaceSets", *resp)
+		if err != nil {
+			return err
+		}
+		networkInterfaceInfos, _ := results.([]interface{})
+		for _, v := range networkInterfaceInfos {
+			info, ok := v.(map[string]interface{})
+			if !ok {
+				continue
 			}
-
-			action = "DescribeInstanceTypes"
-			logger.Debug(logger.ReqFormat, action, instanceTypeId)
-			instanceTypeCondition := map[string]interface{}{
-				"InstanceTypeIds.1": instanceTypeId,
+			id, _ := info["NetworkInterfaceId"].(string)
+			instance, ok := primary[id]
+			if !ok {
+				continue
 			}
-			logger.Debug(logger.ReqFormat, action, instanceTypeCondition)
-			resp, _err = s.Client.EcsClient.DescribeInstanceTypesCommon(&instanceTypeCondition)
-			if _err != nil {
-				syncMap.Store(instanceTypeId, err)
-				return
+			if ipv6Sets, ok := info["IPv6Sets"].([]interface{}); ok {
+				instance["Ipv6Addresses"] = ipv6Sets
+				instance["Ipv6AddressCount"] = len(ipv6Sets)
 			}
-			logger.Debug(logger.RespFormat, action, instanceTypeCondition, *resp)
-			results, _err = bp.ObtainSdkValue("Result.InstanceTypes.0", *resp)
-			if _err != nil {
-				syncMap.Store(instanceTypeId, err)
-				return
+		}
+		return nil
+	})
+}
+
+// instanceFields the string field of every instance
+func instanceFields(instances []interface{}, field string) (result []string) {
+	for _, v := range instances {
+		if instance, ok := v.(map[string]interface{}); ok {
+			if value, ok := instance[field].(string); ok && value != "" {
+				result = append(result, value)
 			}
-			syncMap.Store(instanceTypeId, results)
-		}()
+		}
 	}
-	wg.Wait()
-	var errorStr string
-	for _, instance := range sourceData {
-		var (
-			instanceTypeId interface{}
-			gpu            interface{}
-			gpuDevices     interface{}
-		)
-		instanceTypeId, err = bp.ObtainSdkValue("InstanceTypeId", instance)
+	return result
+}
+
+// readInstanceTypes merge the gpu info of instance types, the instances of failed batches are kept and
+// the errors of the batches are returned
+func (s *VestackEcsService) readInstanceTypes(sourceData []interface{}) (extraData []interface{}, err error) {
+	instanceTypes := make(map[string]interface{})
+	err = bp.WithBatchQuery(instanceFields(sourceData, "InstanceTypeId"), ecsBatchSize, func(batch []string) error {
+		condition := make(map[string]interface{})
+		for i, id := range batch {
+			condition[fmt.Sprintf("InstanceTypeIds.%d", i+1)] = id
+		}
+		results, err := bp.WithNextTokenQuery(condition, "MaxResults", "NextToken", ecsBatchSize, nil, func(m map[string]interface{}) ([]interface{}, string, error) {
+			action := "DescribeInstanceTypes"
+			logger.Debug(logger.ReqFormat, action, m)
+			resp, err := s.Client.EcsClient.DescribeInstanceTypesCommon(&m)
+			if err != nil {
+				return nil, "", err
+			}
+			logger.Debug(logger.RespFormat, action, m, *resp)
+			results, err := bp.ObtainSdkValue("Result.InstanceTypes", *resp)
+			if err != nil {
+				return nil, "", err
+			}
+			nextToken, _ := bp.ObtainSdkValue("Result.NextToken", *resp)
+			next, _ := nextToken.(string)
+			data, _ := results.([]interface{})
+			return data, next, nil
+		})
 		if err != nil {
-			return
+			return err
 		}
-		if v, ok := syncMap.Load(instanceTypeId); ok {
-			if e1, ok1 := v.(error); ok1 {
-				errorStr = errorStr + e1.Error() + ";"
+		for _, v := range results {
+			if instanceType, ok := v.(map[string]interface{}); ok {
+				if id, ok := instanceType["InstanceTypeId"].(string); ok {
+					instanceTypes[id] = instanceType
+				}
 			}
+		}
+		return nil
+	})
+
+	for _, instance := range sourceData {
+		var (
+			gpu        interface{}
+			gpuDevices interface{}
+		)
+		instanceTypeId, _ := instance.(map[string]interface{})["InstanceTypeId"].(string)
+		if v, ok := instanceTypes[instanceTypeId]; ok {
 			gpu, _ = bp.ObtainSdkValue("Gpu", v)
 			if gpu != nil {
 				gpuDevices, _ = bp.ObtainSdkValue("Gpu.GpuDevices", v)
@@ -1539,90 +1531,60 @@ func (s *VestackEcsService) readInstanceTypes(sourceData []interface{}) (extraDa
 		}
 		extraData = append(extraData, instance)
 	}
-	if len(errorStr) > 0 {
-		return extraData, fmt.Errorf(errorStr)
-	}
 	return extraData, err
 }
 
+// readEbsVolumes merge the volumes of instances, DescribeVolumes filters one InstanceId a request, so the
+// instances are read concurrently. The failed instances are kept without volumes and their errors are returned
 func (s *VestackEcsService) readEbsVolumes(sourceData []interface{}) (extraData []interface{}, err error) {
-	//merge ebs
 	var (
-		wg      sync.WaitGroup
-		syncMap sync.Map
+		lock    sync.Mutex
+		volumes = make(map[string][]interface{})
 	)
-	if len(sourceData) == 0 {
-		return sourceData, err
-	}
-	wg.Add(len(sourceData))
-	for _, data := range sourceData {
-		instance := data
-		var (
-			instanceId interface{}
-			action     string
-			resp       *map[string]interface{}
-			results    interface{}
-			_err       error
-		)
-		go func() {
-			defer func() {
-				if e := recover(); e != nil {
-					logger.Debug(logger.ReqFormat, action, e)
-				}
-				bp.Release()
-				wg.Done()
-			}()
-			bp.Acquire()
-
-			instanceId, _err = bp.ObtainSdkValue("InstanceId", instance)
-			if _err != nil {
-				syncMap.Store(instanceId, err)
-				return
-			}
-			action = "DescribeVolumes"
-			logger.Debug(logger.ReqFormat, action, instanceId)
-			volumeCondition := map[string]interface{}{
-				"InstanceId": instanceId,
-			}
-			logger.Debug(logger.ReqFormat, action, volumeCondition)
-			resp, _err = s.Client.EbsClient.DescribeVolumesCommon(&volumeCondition)
-			if _err != nil {
-				syncMap.Store(instanceId, err)
-				return
-			}
-			logger.Debug(logger.RespFormat, action, volumeCondition, *resp)
-			results, _err = bp.ObtainSdkValue("Result.Volumes", *resp)
-			if _err != nil {
-				syncMap.Store(instanceId, err)
-				return
-			}
-			syncMap.Store(instanceId, results)
-		}()
-	}
-	wg.Wait()
-	var errorStr string
-	for _, instance := range sourceData {
-		var (
-			instanceId interface{}
-		)
-		instanceId, err = bp.ObtainSdkValue("InstanceId", instance)
+	err = bp.WithParallelBatchQuery(s.Client, instanceFields(sourceData, "InstanceId"), 1, func(batch []string) error {
+		results, err := bp.WithParallelPageNumberQuery(s, map[string]interface{}{
+			"InstanceId": batch[0],
+		}, "PageSize", "PageNumber", ecsBatchSize, 1, s.describeVolumes)
 		if err != nil {
-			return
+			return err
 		}
-		if v, ok := syncMap.Load(instanceId); ok {
-			if e1, ok1 := v.(error); ok1 {
-				errorStr = errorStr + e1.Error() + ";"
-			}
+		if results == nil {
+			results = []interface{}{}
+		}
+		lock.Lock()
+		volumes[batch[0]] = results
+		lock.Unlock()
+		return nil
+	})
+
+	for _, instance := range sourceData {
+		instanceId, _ := instance.(map[string]interface{})["InstanceId"].(string)
+		if v, ok := volumes[instanceId]; ok {
 			instance.(map[string]interface{})["Volumes"] = v
 		}
 		extraData = append(extraData, instance)
 	}
-	if len(errorStr) > 0 {
-		return extraData, fmt.Errorf(errorStr)
-	}
 	return extraData, err
 }
 
+func (s *VestackEcsService) describeVolumes(condition map[string]interface{}) ([]interface{}, int, error) {
+	action := "DescribeVolumes"
+	logger.Debug(logger.ReqFormat, action, condition)
+	resp, err := s.Client.EbsClient.DescribeVolumesCommon(&condition)
+	if err != nil {
+		return nil, 0, err
+	}
+	logger.Debug(logger.RespFormat, action, condition, *resp)
+	results, err := bp.ObtainSdkValue("Result.Volumes", *resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, _ := bp.ObtainSdkValue("Result.TotalCount", *resp)
+	total, _ := totalCount.(float64)
+	data, _ := results.([]interface{})
+	return data, int(total), nil
+}
+
 func getUniversalInfo(actionName string) bp.UniversalInfo {
 	return bp.UniversalInfo{
 		ServiceName: "ecs",
@@ -1703,3 +1665,19 @@ func (s *VestackEcsService) UnsubscribeInfo(resourceData *schema.ResourceData, r
 	}
 	return &info, nil
 }
+
+func (s *VestackEcsService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "instance",
+		Flavor:         bp.TagFlavorEcs,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
+
+func (s *VestackEcsService) DiffConstraints() []bp.DiffConstraint {
+	return []bp.DiffConstraint{
+		bp.When("keep_image_credential", true).Conflicts("password", "key_pair_name"),
+		bp.When("instance_charge_type", "PrePaid").IntBetween(1, 36, "period"),
+	}
+}
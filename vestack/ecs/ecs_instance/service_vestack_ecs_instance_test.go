@@ -0,0 +1,215 @@
+package ecs_instance
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func newMockEcsService(t *testing.T) (*bp.MockServer, *VestackEcsService) {
+	server := bp.NewMockServer()
+	if err := server.LoadFixturesFile("testdata/describe_instances.json"); err != nil {
+		t.Fatal(err)
+	}
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, NewEcsService(client)
+}
+
+func Test_ReadResource(t *testing.T) {
+	server, svc := newMockEcsService(t)
+	defer server.Close()
+
+	data, err := svc.ReadResource(nil, "i-mock1")
+	assert.Nil(t, err)
+	assert.Equal(t, "RUNNING", data["Status"])
+	assert.Equal(t, []interface{}{"2001:db8::1"}, data["Ipv6Addresses"])
+	assert.Equal(t, 1, data["Ipv6AddressCount"])
+	assert.Equal(t, 1, len(data["Tags"].([]interface{})))
+
+	assert.Equal(t, "i-mock1", server.Requests("DescribeInstances")[0].Params["InstanceIds.1"])
+	assert.Equal(t, "eni-primary", server.Requests("DescribeNetworkInterfaces")[0].Params["NetworkInterfaceIds.1"])
+}
+
+func Test_RemoveResource(t *testing.T) {
+	server, svc := newMockEcsService(t)
+	defer server.Close()
+	server.On("DeleteInstance", bp.MockResponse{Result: map[string]interface{}{}})
+
+	resource := ResourceVestackEcsInstance()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"instance_charge_type": "PostPaid",
+	})
+	d.SetId("i-mock1")
+
+	err := bp.NewRateLimitDispatcher(rateInfo).Delete(svc, d, resource)
+	assert.Nil(t, err)
+	requests := server.Requests("DeleteInstance")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "i-mock1", requests[0].Params["InstanceId"])
+	assert.Equal(t, "ecs", requests[0].Service)
+}
+
+func Test_ReadResources_BatchEnrichment(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	var instances []interface{}
+	for i := 1; i <= 3; i++ {
+		instances = append(instances, map[string]interface{}{
+			"InstanceId":     fmt.Sprintf("i-mock%d", i),
+			"InstanceTypeId": "ecs.g1.large",
+			"NetworkInterfaces": []interface{}{
+				map[string]interface{}{"NetworkInterfaceId": fmt.Sprintf("eni-%d", i), "Type": "primary"},
+			},
+		})
+	}
+	server.On("DescribeInstances", bp.MockResponse{Result: map[string]interface{}{"Instances": instances, "NextToken": ""}})
+	server.On("DescribeNetworkInterfaces", bp.MockResponse{Result: map[string]interface{}{"NetworkInterfaceSets": []interface{}{
+		map[string]interface{}{"NetworkInterfaceId": "eni-1", "IPv6Sets": []interface{}{"2001:db8::1"}},
+		map[string]interface{}{"NetworkInterfaceId": "eni-3", "IPv6Sets": []interface{}{"2001:db8::3"}},
+	}}})
+	server.On("DescribeInstanceTypes", bp.MockResponse{Result: map[string]interface{}{"InstanceTypes": []interface{}{
+		map[string]interface{}{"InstanceTypeId": "ecs.g1.large"},
+	}}})
+	server.OnFunc("DescribeVolumes", func(req *bp.MockRequest) *bp.MockResponse {
+		if req.Params["InstanceId"] != "i-mock1" {
+			return &bp.MockResponse{StatusCode: 400, Error: &bp.MockError{Code: "InvalidParameter", Message: "mock"}}
+		}
+		return &bp.MockResponse{Result: map[string]interface{}{"Volumes": []interface{}{
+			map[string]interface{}{"VolumeId": "vol-1", "InstanceId": "i-mock1"},
+		}, "TotalCount": 1}}
+	})
+	client, err := server.Client()
+	assert.Nil(t, err)
+	svc := NewEcsService(client)
+
+	data, err := svc.ReadResources(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(data))
+	assert.Equal(t, []interface{}{"2001:db8::1"}, data[0].(map[string]interface{})["Ipv6Addresses"])
+	assert.Nil(t, data[1].(map[string]interface{})["Ipv6Addresses"])
+	assert.Equal(t, 1, data[2].(map[string]interface{})["Ipv6AddressCount"])
+	requests := server.Requests("DescribeNetworkInterfaces")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "eni-3", requests[0].Params["NetworkInterfaceIds.3"])
+
+	// partial failures keep the instances and return the errors of the failed instances
+	volumes, err := svc.readEbsVolumes(data)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "[i-mock2]")
+		assert.Contains(t, err.Error(), "[i-mock3]")
+	}
+	assert.Equal(t, 3, len(volumes))
+	assert.Equal(t, "vol-1", volumes[0].(map[string]interface{})["Volumes"].([]interface{})[0].(map[string]interface{})["VolumeId"])
+	assert.Nil(t, volumes[1].(map[string]interface{})["Volumes"])
+	assert.Equal(t, 3, len(server.Requests("DescribeVolumes")))
+
+	// the data source fails instead of returning the instances without volumes
+	_, err = svc.DatasourceResources(nil, nil).ExtraData(data)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "read volumes of ecs instances error")
+	}
+	assert.Equal(t, 1, len(server.Requests("DescribeInstanceTypes")))
+	assert.Equal(t, false, data[1].(map[string]interface{})["IsGpu"])
+}
+
+func Test_ReadEbsVolumes_ByInstance(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.OnFunc("DescribeVolumes", func(req *bp.MockRequest) *bp.MockResponse {
+		id, _ := req.Params["InstanceId"].(string)
+		return &bp.MockResponse{Result: map[string]interface{}{"Volumes": []interface{}{
+			map[string]interface{}{"VolumeId": "vol-" + id, "InstanceId": id},
+		}, "TotalCount": 1}}
+	})
+	client, err := server.Client()
+	assert.Nil(t, err)
+	svc := NewEcsService(client)
+
+	var instances []interface{}
+	for i := 1; i <= 25; i++ {
+		instances = append(instances, map[string]interface{}{"InstanceId": fmt.Sprintf("i-mock%d", i)})
+	}
+	data, err := svc.readEbsVolumes(instances)
+	assert.Nil(t, err)
+	assert.Equal(t, 25, len(data))
+	assert.Equal(t, "vol-i-mock25", data[24].(map[string]interface{})["Volumes"].([]interface{})[0].(map[string]interface{})["VolumeId"])
+	requests := server.Requests("DescribeVolumes")
+	assert.Equal(t, 25, len(requests))
+	for _, req := range requests {
+		assert.NotEmpty(t, req.Params["InstanceId"])
+	}
+}
+
+func Test_ReadEbsVolumes_Concurrent(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	var (
+		lock          sync.Mutex
+		running, peak int
+	)
+	server.OnFunc("DescribeVolumes", func(req *bp.MockRequest) *bp.MockResponse {
+		lock.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		lock.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return &bp.MockResponse{Result: map[string]interface{}{"Volumes": []interface{}{}, "TotalCount": 0}}
+	})
+	config := server.Config()
+	config.MaxSyncConcurrency = 4
+	client, err := config.Client()
+	assert.Nil(t, err)
+
+	var instances []interface{}
+	for i := 1; i <= 12; i++ {
+		instances = append(instances, map[string]interface{}{"InstanceId": fmt.Sprintf("i-mock%d", i)})
+	}
+	_, err = NewEcsService(client).readEbsVolumes(instances)
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(server.Requests("DescribeVolumes")))
+	assert.True(t, peak > 1 && peak <= 4, "peak %d", peak)
+}
+
+func Test_DiffConstraints_Period(t *testing.T) {
+	r := ResourceVestackEcsInstance()
+	for _, c := range []struct {
+		chargeType string
+		period     interface{}
+		valid      bool
+	}{
+		{"PrePaid", nil, true},
+		{"PrePaid", 36, true},
+		{"PrePaid", 0, false},
+		{"PrePaid", 48, false},
+		{"PostPaid", 48, true},
+	} {
+		cfg := map[string]interface{}{
+			"image_id":             "image-1",
+			"instance_type":        "ecs.g1.large",
+			"subnet_id":            "subnet-1",
+			"security_group_ids":   []interface{}{"sg-1"},
+			"system_volume_type":   "ESSD_PL0",
+			"system_volume_size":   40,
+			"instance_charge_type": c.chargeType,
+		}
+		if c.period != nil {
+			cfg["period"] = c.period
+		}
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(cfg), nil)
+		assert.Equal(t, c.valid, err == nil, "%s %v %v", c.chargeType, c.period, err)
+	}
+}
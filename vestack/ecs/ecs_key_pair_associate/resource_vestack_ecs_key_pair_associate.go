@@ -2,7 +2,6 @@ package ecs_key_pair_associate
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -24,26 +23,14 @@ $ terraform import vestack_ecs_key_pair_associate.default kp-ybti5tkpkv2udbfolrf
 
 */
 
+var keyPairAssociateCompositeId = bp.NewCompositeId("key_pair_id", "instance_id")
+
 func ResourceVestackEcsKeyPairAssociate() *schema.Resource {
 	resource := &schema.Resource{
-		Create: resourceVestackEcsKeyPairAssociateCreate,
-		Read:   resourceVestackEcsKeyPairAssociateRead,
-		Delete: resourceVestackEcsKeyPairAssociateDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
-				items := strings.Split(data.Id(), ":")
-				if len(items) != 2 {
-					return []*schema.ResourceData{data}, fmt.Errorf("import id must split with ':'")
-				}
-				if err := data.Set("key_pair_id", items[0]); err != nil {
-					return []*schema.ResourceData{data}, err
-				}
-				if err := data.Set("instance_id", items[1]); err != nil {
-					return []*schema.ResourceData{data}, err
-				}
-				return []*schema.ResourceData{data}, nil
-			},
-		},
+		Create:   resourceVestackEcsKeyPairAssociateCreate,
+		Read:     resourceVestackEcsKeyPairAssociateRead,
+		Delete:   resourceVestackEcsKeyPairAssociateDelete,
+		Importer: keyPairAssociateCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
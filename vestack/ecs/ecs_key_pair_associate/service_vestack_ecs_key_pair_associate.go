@@ -45,7 +45,10 @@ func (s *VestackEcsKeyPairAssociateService) ReadResource(resourceData *schema.Re
 		tmpId = s.ReadResourceId(resourceData.Id())
 	}
 
-	ids = strings.Split(tmpId, ":")
+	ids, err = keyPairAssociateCompositeId.Decode(tmpId)
+	if err != nil {
+		return data, err
+	}
 	keyPairId = ids[0]
 	targetInstanceId = ids[1]
 
@@ -152,7 +155,11 @@ func (s *VestackEcsKeyPairAssociateService) CreateResource(resourceData *schema.
 				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId((*call.SdkParam)["KeyPairId"].(string) + ":" + (*call.SdkParam)["InstanceIds.1"].(string))
+				id, err := keyPairAssociateCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 			Refresh: &bp.StateRefresh{
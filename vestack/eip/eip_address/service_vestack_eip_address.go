@@ -279,10 +279,6 @@ func (s *VestackEipAddressService) ModifyResource(resourceData *schema.ResourceD
 		callbacks = append(callbacks, chargeTypeCall)
 	}
 
-	// 更新Tags
-	setResourceTagsCallbacks := bp.SetResourceTags(s.Client, "TagResources", "UntagResources", "eip", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -399,3 +395,12 @@ func (s *VestackEipAddressService) UnsubscribeInfo(resourceData *schema.Resource
 	}
 	return &info, nil
 }
+
+func (s *VestackEipAddressService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "eip",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
@@ -1,22 +1,7 @@
 package eip_associate
 
 import (
-	"fmt"
-	"strings"
-
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
 )
 
-var eipAssociateImporter = func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
-	items := strings.Split(data.Id(), ":")
-	if len(items) != 2 {
-		return []*schema.ResourceData{data}, fmt.Errorf("import id must split with ':'")
-	}
-	if err := data.Set("allocation_id", items[0]); err != nil {
-		return []*schema.ResourceData{data}, err
-	}
-	if err := data.Set("instance_id", items[1]); err != nil {
-		return []*schema.ResourceData{data}, err
-	}
-	return []*schema.ResourceData{data}, nil
-}
+var eipAssociateCompositeId = bp.NewCompositeId("allocation_id", "instance_id")
@@ -20,12 +20,10 @@ $ terraform import vestack_eip_associate.default eip-274oj9a8rs9a87fap8sf9515b:i
 
 func ResourceVestackEipAssociate() *schema.Resource {
 	return &schema.Resource{
-		Delete: resourceVestackEipAssociateDelete,
-		Create: resourceVestackEipAssociateCreate,
-		Read:   resourceVestackEipAssociateRead,
-		Importer: &schema.ResourceImporter{
-			State: eipAssociateImporter,
-		},
+		Delete:   resourceVestackEipAssociateDelete,
+		Create:   resourceVestackEipAssociateCreate,
+		Read:     resourceVestackEipAssociateRead,
+		Importer: eipAssociateCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
@@ -44,7 +44,10 @@ func (s *VestackEipAssociateService) ReadResource(resourceData *schema.ResourceD
 		tmpId = s.ReadResourceId(resourceData.Id())
 	}
 
-	ids = strings.Split(tmpId, ":")
+	ids, err = eipAssociateCompositeId.Decode(tmpId)
+	if err != nil {
+		return data, err
+	}
 	allocationId = ids[0]
 	targetInstanceId = ids[1]
 
@@ -134,7 +137,11 @@ func (s *VestackEipAssociateService) CreateResource(resourceData *schema.Resourc
 				return s.Client.VpcClient.AssociateEipAddressCommon(call.SdkParam)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId(fmt.Sprint((*call.SdkParam)["AllocationId"], ":", (*call.SdkParam)["InstanceId"]))
+				id, err := eipAssociateCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 			Refresh: &bp.StateRefresh{
@@ -76,6 +76,7 @@ func ResourceVestackIamRole() *schema.Resource {
 				Computed:    true,
 				Description: "The resource name of the Role.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 }
@@ -86,6 +86,12 @@ func (s *VestackIamRoleService) ReadResource(resourceData *schema.ResourceData,
 	if len(data) == 0 {
 		return data, fmt.Errorf("Role %s not exist ", roleId)
 	}
+	// ListUsers and ListRoles don't return tags
+	tags, err := bp.NewTagService(s.Client).ResourceTags(s.TagInfo(), roleId)
+	if err != nil {
+		return data, err
+	}
+	data["Tags"] = tags
 	return data, err
 }
 
@@ -120,6 +126,11 @@ func (s *VestackIamRoleService) CreateResource(data *schema.ResourceData, resour
 		Call: bp.SdkCall{
 			Action:      "CreateRole",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
 				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
@@ -142,6 +153,11 @@ func (s *VestackIamRoleService) ModifyResource(data *schema.ResourceData, resour
 		Call: bp.SdkCall{
 			Action:      "UpdateRole",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
 				(*call.SdkParam)["RoleName"] = d.Get("role_name")
 				return true, nil
@@ -217,3 +233,12 @@ func getUniversalInfo(actionName string) bp.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackIamRoleService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "Role",
+		Flavor:         bp.TagFlavorIam,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -59,6 +59,7 @@ func ResourceVestackIamUser() *schema.Resource {
 				Optional:    true,
 				Description: "The description of the user.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 	bp.MergeDateSourceToResource(DataSourceVestackIamUsers().Schema["users"].Elem.(*schema.Resource).Schema, &resource.Schema)
@@ -112,6 +112,12 @@ func (s *VestackIamUserService) ReadResource(resourceData *schema.ResourceData,
 		return data, fmt.Errorf("user %s not exist ", id)
 	}
 
+	// ListUsers and ListRoles don't return tags
+	tags, err := bp.NewTagService(s.Client).ResourceTags(s.TagInfo(), id)
+	if err != nil {
+		return data, err
+	}
+	data["Tags"] = tags
 	return data, err
 }
 
@@ -139,6 +145,11 @@ func (s *VestackIamUserService) CreateResource(resourceData *schema.ResourceData
 		Call: bp.SdkCall{
 			Action:      "CreateUser",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.RespFormat, call.Action, call.SdkParam)
 				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
@@ -249,3 +260,12 @@ func getUniversalInfo(actionName string) bp.UniversalInfo {
 		ContentType: bp.Default,
 	}
 }
+
+func (s *VestackIamUserService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "User",
+		Flavor:         bp.TagFlavorIam,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -250,10 +250,6 @@ func (s *VestackNatGatewayService) ModifyResource(resourceData *schema.ResourceD
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "ngw", resourceData, getVpcUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -400,3 +396,12 @@ func (s *VestackNatGatewayService) UnsubscribeInfo(resourceData *schema.Resource
 	}
 	return &info, nil
 }
+
+func (s *VestackNatGatewayService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "ngw",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getVpcUniversalInfo,
+		CreateWithTags: true,
+	}
+}
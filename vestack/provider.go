@@ -1,7 +1,9 @@
 package vestack
 
 import (
-	"github.com/volcengine/terraform-provider-vestack/logger"
+	"context"
+	"time"
+
 	"github.com/volcengine/terraform-provider-vestack/vestack/clb/acl"
 	"github.com/volcengine/terraform-provider-vestack/vestack/clb/acl_entry"
 	"github.com/volcengine/terraform-provider-vestack/vestack/clb/certificate"
@@ -72,6 +74,7 @@ import (
 	//"github.com/volcengine/terraform-provider-vestack/vestack/mongodb/spec"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	ve "github.com/volcengine/terraform-provider-vestack/common"
 
@@ -95,6 +98,8 @@ import (
 	"github.com/volcengine/terraform-provider-vestack/vestack/ecs/zone"
 	"github.com/volcengine/terraform-provider-vestack/vestack/eip/eip_address"
 	"github.com/volcengine/terraform-provider-vestack/vestack/eip/eip_associate"
+	"github.com/volcengine/terraform-provider-vestack/vestack/tag/resources_by_tag"
+	"github.com/volcengine/terraform-provider-vestack/vestack/tag/tags"
 
 	//"github.com/volcengine/terraform-provider-vestack/vestack/escloud/instance"
 	//"github.com/volcengine/terraform-provider-vestack/vestack/escloud/region"
@@ -181,19 +186,19 @@ import (
 )
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"access_key": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VESTACK_ACCESS_KEY", nil),
-				Description: "The Access Key for Vestack Provider",
+				Description: "The Access Key for Vestack Provider. When not set, the credentials are loaded from the environment or shared credentials file",
 			},
 			"secret_key": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VESTACK_SECRET_KEY", nil),
-				Description: "The Secret Key for Vestack Provider",
+				Description: "The Secret Key for Vestack Provider. When not set, the credentials are loaded from the environment or shared credentials file",
 			},
 			"session_token": {
 				Type:        schema.TypeString,
@@ -201,6 +206,51 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("VESTACK_SESSION_TOKEN", nil),
 				Description: "The Session Token for Vestack Provider",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VESTACK_PROFILE", nil),
+				Description: "The profile of shared credentials file. Default is `default`",
+			},
+			"shared_credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VESTACK_SHARED_CREDENTIALS_FILE", nil),
+				Description: "The path of shared credentials file. Default is `~/.volcengine/credentials`",
+			},
+			"assume_role": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Assume a role by sts with the credentials above. The session token is refreshed before expired",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_trn": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The trn of the role, e.g. `trn:iam::2100000000:role/terraform`",
+						},
+						"session_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The session name of the assumed role",
+						},
+						"duration_seconds": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3600,
+							ValidateFunc: validation.IntBetween(900, 43200),
+							Description:  "The duration of the session token in seconds. Default is 3600",
+						},
+						"policy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.ValidateJsonString,
+							Description:  "The json policy further restrict the permissions of the assumed role",
+						},
+					},
+				},
+			},
 			"region": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -231,12 +281,252 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("VESTACK_CUSTOMER_ENDPOINTS", nil),
 				Description: "CUSTOMER ENDPOINTS for Vestack Provider",
 			},
+			"service_overrides": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The endpoint, timeout and retries of a service. The endpoint overrides customer_endpoints",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The service name, e.g. `ecs`, `vpc`, `clb`, `storage_ebs`, `vke`, `iam`, `sts`, `tos`, `tls`",
+						},
+						"endpoint": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The endpoint host of the service, port and scheme are allowed, e.g. `ecs.example.com:8443`",
+						},
+						"scheme": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"http", "https"}, false),
+							Description:  "The scheme of the endpoint. Valid values: `http`, `https`. Default is decided by disable_ssl",
+						},
+						"signing_region": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The region used to sign the requests of the service. Default is the provider region",
+						},
+						"timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ve.ValidateDuration,
+							Description:  "The timeout of a http request of the service, e.g. `30s`",
+						},
+						"max_retries": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      -1,
+							ValidateFunc: validation.IntAtLeast(-1),
+							Description:  "The max retries of the sdk for a request of the service, in addition to the `retry` policy of the provider. Default is -1, the sdk never retries and the requests are only retried by the `retry` policy",
+						},
+					},
+				},
+			},
 			"proxy_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VESTACK_PROXY_URL", nil),
 				Description: "PROXY URL for Vestack Provider",
 			},
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The tags added to every resource with tags. The tags of a resource override the default tags with the same key",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The default tags of resources",
+						},
+					},
+				},
+			},
+			"ignore_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The tags which are managed outside of terraform. They are never read into state and never removed",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The tag keys to ignore",
+						},
+						"key_prefixes": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The tag key prefixes to ignore",
+						},
+					},
+				},
+			},
+			"http_client": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The http transport of the provider: proxy exclusions, tls and connection pool",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"no_proxy": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							DefaultFunc: schema.EnvDefaultFunc("VESTACK_NO_PROXY", nil),
+							Description: "Comma separated hosts, domains or CIDRs which bypass proxy_url. Default is the VESTACK_NO_PROXY env, then the NO_PROXY env",
+						},
+						"ca_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							DefaultFunc: schema.EnvDefaultFunc("VESTACK_CA_FILE", nil),
+							Description: "The PEM bundle of CA certificates trusted besides the system roots. Default is the VESTACK_CA_FILE env",
+						},
+						"client_cert_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The PEM client certificate of mutual TLS",
+						},
+						"client_key_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The PEM client private key of mutual TLS",
+						},
+						"tls_min_version": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+							Description:  "The minimum TLS version. Valid values: `1.0`, `1.1`, `1.2`, `1.3`",
+						},
+						"max_idle_conns": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The max idle connections of all hosts. Default is 100",
+						},
+						"max_idle_conns_per_host": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The max idle connections of a host. Default is 10",
+						},
+						"max_conns_per_host": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The max connections of a host. 0 means no limit",
+						},
+						"timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ve.ValidateDuration,
+							Description:  "The timeout of a http request, e.g. `60s`. Default is no timeout",
+						},
+					},
+				},
+			},
+			"cassette_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VESTACK_CASSETTE_MODE", nil),
+				ValidateFunc: validation.StringInSlice([]string{ve.CassetteModeRecord, ve.CassetteModeReplay}, false),
+				Description:  "Record every api request to cassette_file, or replay them from cassette_file. Valid values: `record`, `replay`",
+			},
+			"cassette_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VESTACK_CASSETTE_FILE", nil),
+				Description: "The cassette file used by cassette_mode",
+			},
+			"max_sync_concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VESTACK_MAX_SYNC_CONCURRENCY", 10),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The max concurrency of requests reading extra info of a resource. Default is 10",
+			},
+			"read_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VESTACK_READ_CACHE", false),
+				Description: "Cache the results of data source and resource reads with the same conditions in one run. The cache is dropped after every create, update and delete. Default is false",
+			},
+			"retry": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The retry policy of throttled, conflict and transient errors of api requests",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      ve.DefaultRetryPolicy.MaxAttempts,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "The max attempts of a request, include the first one. 1 means no retry. Default is 10",
+						},
+						"base_delay": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      ve.DefaultRetryPolicy.BaseDelay.String(),
+							ValidateFunc: ve.ValidateDuration,
+							Description:  "The delay before the first retry, doubled on every retry with jitter. Default is `1s`",
+						},
+						"max_delay": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      ve.DefaultRetryPolicy.MaxDelay.String(),
+							ValidateFunc: ve.ValidateDuration,
+							Description:  "The max delay between two retries. Default is `30s`",
+						},
+					},
+				},
+			},
+			"rate_limit": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The rate limit of api requests. The most specific block of service and operation takes effect",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The api service of the rate limit, e.g. `ecs`, `vpc`, `clb`, `vke`, `iam`, `tos`. The eip resources are `vpc`. Empty means all services",
+						},
+						"operation": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(ve.RateOperations, false),
+							Description:  "The operation of the rate limit. Valid values: `create`, `read`, `update`, `delete`, `data`. Empty means all operations",
+						},
+						"qps": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							ValidateFunc: validation.FloatAtLeast(0),
+							Description:  "The max operations per second. 0 means no limit",
+						},
+						"burst": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The burst of qps. Default is 1",
+						},
+						"max_concurrency": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The max concurrent operations. 0 means no limit",
+						},
+					},
+				},
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"vestack_vpcs":                        vpc.DataSourceVestackVpcs(),
@@ -251,6 +541,10 @@ func Provider() terraform.ResourceProvider {
 			"vestack_vpc_ipv6_address_bandwidths": ipv6_address_bandwidth.DataSourceVestackIpv6AddressBandwidths(),
 			"vestack_vpc_ipv6_addresses":          ipv6_address.DataSourceVestackIpv6Addresses(),
 
+			// ================ TAG ================
+			"vestack_tags":             tags.DataSourceVestackTags(),
+			"vestack_resources_by_tag": resources_by_tag.DataSourceVestackResourcesByTag(),
+
 			// ================ EIP ================
 			"vestack_eip_addresses": eip_address.DataSourceVestackEipAddresses(),
 
@@ -755,45 +1049,158 @@ func Provider() terraform.ResourceProvider {
 			//"vestack_cloudfs_access":      cloudfs_access.ResourceVestackCloudfsAccess(),
 			//"vestack_cloudfs_namespace":   cloudfs_namespace.ResourceVestackCloudfsNamespace(),
 		},
-		ConfigureFunc: ProviderConfigure,
 	}
+	for name, resource := range provider.ResourcesMap {
+		ve.ResourceDefaultTags(resource)
+		// iam is a global service
+		if !strings.HasPrefix(name, "vestack_iam_") {
+			ve.ResourceRegionOverride(resource)
+		}
+	}
+	for _, dataSource := range provider.DataSourcesMap {
+		ve.WrapDataSource(dataSource)
+	}
+	ve.RegisterSensitiveFields(provider.ResourcesMap)
+	ve.RegisterSensitiveFields(provider.DataSourcesMap)
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, provider.StopContext())
+	}
+	return provider
 }
 
 func ProviderConfigure(d *schema.ResourceData) (interface{}, error) {
+	return providerConfigure(d, context.Background())
+}
+
+// providerConfigure stopCtx is cancelled when terraform stop the provider, in-flight calls and polling abort with it
+func providerConfigure(d *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
 	config := ve.Config{
-		AccessKey:         d.Get("access_key").(string),
-		SecretKey:         d.Get("secret_key").(string),
-		SessionToken:      d.Get("session_token").(string),
-		Region:            d.Get("region").(string),
-		Endpoint:          d.Get("endpoint").(string),
-		DisableSSL:        d.Get("disable_ssl").(bool),
-		CustomerHeaders:   map[string]string{},
-		CustomerEndpoints: defaultCustomerEndPoints(),
-		ProxyUrl:          d.Get("proxy_url").(string),
+		AccessKey:             d.Get("access_key").(string),
+		SecretKey:             d.Get("secret_key").(string),
+		SessionToken:          d.Get("session_token").(string),
+		Region:                d.Get("region").(string),
+		Endpoint:              d.Get("endpoint").(string),
+		DisableSSL:            d.Get("disable_ssl").(bool),
+		CustomerHeaders:       map[string]string{},
+		CustomerEndpoints:     defaultCustomerEndPoints(),
+		ProxyUrl:              d.Get("proxy_url").(string),
+		CassetteMode:          d.Get("cassette_mode").(string),
+		CassetteFile:          d.Get("cassette_file").(string),
+		StopContext:           stopCtx,
+		MaxSyncConcurrency:    d.Get("max_sync_concurrency").(int),
+		ReadCache:             d.Get("read_cache").(bool),
+		Profile:               d.Get("profile").(string),
+		SharedCredentialsFile: d.Get("shared_credentials_file").(string),
+	}
+	if v, ok := d.GetOk("assume_role"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		m := v.([]interface{})[0].(map[string]interface{})
+		config.AssumeRole = &ve.AssumeRole{
+			RoleTrn:         m["role_trn"].(string),
+			SessionName:     m["session_name"].(string),
+			DurationSeconds: m["duration_seconds"].(int),
+			Policy:          m["policy"].(string),
+		}
+	}
+	if v, ok := d.GetOk("default_tags"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		config.DefaultTags = make(map[string]string)
+		for k, tag := range v.([]interface{})[0].(map[string]interface{})["tags"].(map[string]interface{}) {
+			config.DefaultTags[k] = tag.(string)
+		}
+	}
+	if v, ok := d.GetOk("ignore_tags"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		m := v.([]interface{})[0].(map[string]interface{})
+		for _, key := range m["keys"].(*schema.Set).List() {
+			config.IgnoreTags.Keys = append(config.IgnoreTags.Keys, key.(string))
+		}
+		for _, prefix := range m["key_prefixes"].(*schema.Set).List() {
+			config.IgnoreTags.KeyPrefixes = append(config.IgnoreTags.KeyPrefixes, prefix.(string))
+		}
+	}
+	if v, ok := d.GetOk("http_client"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		m := v.([]interface{})[0].(map[string]interface{})
+		config.Transport = ve.HttpTransport{
+			NoProxy:             m["no_proxy"].(string),
+			CaFile:              m["ca_file"].(string),
+			ClientCertFile:      m["client_cert_file"].(string),
+			ClientKeyFile:       m["client_key_file"].(string),
+			TlsMinVersion:       m["tls_min_version"].(string),
+			MaxIdleConns:        m["max_idle_conns"].(int),
+			MaxIdleConnsPerHost: m["max_idle_conns_per_host"].(int),
+			MaxConnsPerHost:     m["max_conns_per_host"].(int),
+		}
+		if timeout := m["timeout"].(string); timeout != "" {
+			config.Transport.Timeout, _ = time.ParseDuration(timeout)
+		}
+	} else {
+		// the env defaults of the block only take effect when the block is declared
+		config.Transport = ve.HttpTransportFromEnv()
+	}
+	if v, ok := d.GetOk("retry"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		m := v.([]interface{})[0].(map[string]interface{})
+		policy := ve.DefaultRetryPolicy
+		policy.MaxAttempts = m["max_attempts"].(int)
+		policy.BaseDelay, _ = time.ParseDuration(m["base_delay"].(string))
+		policy.MaxDelay, _ = time.ParseDuration(m["max_delay"].(string))
+		config.RetryPolicy = &policy
+	}
+	for _, v := range d.Get("rate_limit").([]interface{}) {
+		if v == nil {
+			continue
+		}
+		m := v.(map[string]interface{})
+		config.RateLimits = append(config.RateLimits, ve.RateLimit{
+			Service:        m["service"].(string),
+			Operation:      m["operation"].(string),
+			Qps:            m["qps"].(float64),
+			Burst:          m["burst"].(int),
+			MaxConcurrency: m["max_concurrency"].(int),
+		})
 	}
-	logger.Info("access_key: %+v", config.AccessKey)
 	headers := d.Get("customer_headers").(string)
 	if headers != "" {
 		hs1 := strings.Split(headers, ",")
 		for _, hh := range hs1 {
-			hs2 := strings.Split(hh, ":")
+			hs2 := strings.SplitN(hh, ":", 2)
 			if len(hs2) == 2 {
 				config.CustomerHeaders[hs2[0]] = hs2[1]
 			}
 		}
 	}
 
+	// service:host[:port], the host may contain port
 	endpoints := d.Get("customer_endpoints").(string)
 	if endpoints != "" {
 		ends := strings.Split(endpoints, ",")
 		for _, end := range ends {
-			point := strings.Split(end, ":")
+			point := strings.SplitN(strings.TrimSpace(end), ":", 2)
 			if len(point) == 2 {
 				config.CustomerEndpoints[point[0]] = point[1]
 			}
 		}
 	}
 
+	for _, v := range d.Get("service_overrides").([]interface{}) {
+		if v == nil {
+			continue
+		}
+		m := v.(map[string]interface{})
+		if config.ServiceOverrides == nil {
+			config.ServiceOverrides = make(map[string]ve.ServiceOverride)
+		}
+		override := ve.ServiceOverride{
+			Endpoint:      m["endpoint"].(string),
+			Scheme:        m["scheme"].(string),
+			SigningRegion: m["signing_region"].(string),
+		}
+		if timeout := m["timeout"].(string); timeout != "" {
+			override.Timeout, _ = time.ParseDuration(timeout)
+		}
+		if maxRetries := m["max_retries"].(int); maxRetries >= 0 {
+			override.MaxRetries = &maxRetries
+		}
+		config.ServiceOverrides[m["service"].(string)] = override
+	}
+
 	client, err := config.Client()
 	return client, err
 }
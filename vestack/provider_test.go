@@ -0,0 +1,76 @@
+package vestack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	ve "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func Test_Provider(t *testing.T) {
+	assert.Nil(t, Provider().(*schema.Provider).InternalValidate())
+}
+
+func Test_DataSourceFilter_Provider(t *testing.T) {
+	server := ve.NewMockServer()
+	defer server.Close()
+	server.On("DescribeVpcs", ve.MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{
+		map[string]interface{}{"VpcId": "vpc-1", "VpcName": "web", "Status": "Available"},
+		map[string]interface{}{"VpcId": "vpc-2", "VpcName": "db", "Status": "Available"},
+		map[string]interface{}{"VpcId": "vpc-3", "VpcName": "web", "Status": "Pending"},
+	}, "TotalCount": 3}}, ve.MockResponse{Result: map[string]interface{}{"Vpcs": []interface{}{}, "TotalCount": 3}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Provider().(*schema.Provider).DataSourcesMap["vestack_vpcs"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"name": "vpc_name", "values": []interface{}{"web"}},
+			map[string]interface{}{"name": "status", "values": []interface{}{"Avail*"}, "mode": "glob"},
+		},
+	})
+	assert.Nil(t, r.Read(d, client))
+	assert.Equal(t, 1, d.Get("total_count"))
+	assert.Equal(t, "vpc-1", d.Get("vpcs.0.vpc_id"))
+}
+
+func Test_DataSourceTagFilter_Provider(t *testing.T) {
+	server := ve.NewMockServer()
+	defer server.Close()
+	server.On("DescribeSubnets", ve.MockResponse{Result: map[string]interface{}{"Subnets": []interface{}{
+		map[string]interface{}{"SubnetId": "subnet-1"},
+	}, "TotalCount": 1}}, ve.MockResponse{Result: map[string]interface{}{"Subnets": []interface{}{}, "TotalCount": 1}})
+	server.On("DescribeNetworkAcls", ve.MockResponse{Result: map[string]interface{}{"NetworkAcls": []interface{}{
+		map[string]interface{}{"NetworkAclId": "acl-1", "Tags": []interface{}{
+			map[string]interface{}{"Key": "env", "Value": "dev"},
+		}},
+		map[string]interface{}{"NetworkAclId": "acl-2", "Tags": []interface{}{
+			map[string]interface{}{"Key": "env", "Value": "prod"},
+		}},
+	}, "TotalCount": 2}}, ve.MockResponse{Result: map[string]interface{}{"NetworkAcls": []interface{}{}, "TotalCount": 2}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := []interface{}{
+		map[string]interface{}{"key": "env", "value": "dev"},
+	}
+
+	// sent as TagFilters
+	r := Provider().(*schema.Provider).DataSourcesMap["vestack_subnets"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"tags": tags})
+	assert.Nil(t, r.Read(d, client))
+	params := server.Requests("DescribeSubnets")[0].Params
+	assert.Equal(t, "env", params["TagFilters.1.Key"])
+	assert.Equal(t, "dev", params["TagFilters.1.Values.1"])
+
+	// filtered by the Tags of the results
+	r = Provider().(*schema.Provider).DataSourcesMap["vestack_network_acls"]
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"tags": tags})
+	assert.Nil(t, r.Read(d, client))
+	assert.Equal(t, 1, d.Get("total_count"))
+	assert.Equal(t, "acl-1", d.Get("network_acls.0.network_acl_id"))
+}
@@ -0,0 +1,74 @@
+package resources_by_tag
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func DataSourceVestackResourcesByTag() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVestackResourcesByTagRead,
+		Schema: map[string]*schema.Schema{
+			"resource_types": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of resource types in the format of `service:type`, such as `vpc:vpc` and `ecs:instance`. Default is all resource types.",
+			},
+			"tags": bp.TagsSchema(),
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results.",
+			},
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The total count of resource query.",
+			},
+			"resources": {
+				Description: "The collection of resource query.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"trn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The trn of the resource.",
+						},
+						"resource_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the resource.",
+						},
+						"resource_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the resource.",
+						},
+						"service_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The service name of the resource.",
+						},
+						"region": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The region of the resource.",
+						},
+						"tags": bp.TagsSchemaComputed(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVestackResourcesByTagRead(d *schema.ResourceData, meta interface{}) error {
+	resourceService := NewResourcesByTagService(meta.(*bp.SdkClient))
+	return bp.DefaultDispatcher().Data(resourceService, d, DataSourceVestackResourcesByTag())
+}
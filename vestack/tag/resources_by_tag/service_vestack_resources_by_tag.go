@@ -0,0 +1,132 @@
+package resources_by_tag
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+)
+
+type VestackResourcesByTagService struct {
+	Client *bp.SdkClient
+}
+
+func NewResourcesByTagService(c *bp.SdkClient) *VestackResourcesByTagService {
+	return &VestackResourcesByTagService{
+		Client: c,
+	}
+}
+
+func (s *VestackResourcesByTagService) GetClient() *bp.SdkClient {
+	return s.Client
+}
+
+func (s *VestackResourcesByTagService) ReadResources(condition map[string]interface{}) (data []interface{}, err error) {
+	if condition == nil {
+		condition = make(map[string]interface{})
+	}
+	// TagFilters of GetResources are Key and Values
+	if filters, ok := condition["TagFilters"].([]interface{}); ok {
+		for _, filter := range filters {
+			if m, ok := filter.(map[string]interface{}); ok {
+				if value, exist := m["Value"]; exist {
+					m["Values"] = []interface{}{value}
+					delete(m, "Value")
+				}
+			}
+		}
+	}
+	return bp.WithNextTokenQuery(condition, "MaxResults", "NextToken", 100, nil, func(m map[string]interface{}) ([]interface{}, string, error) {
+		action := "GetResources"
+		logger.Debug(logger.ReqFormat, action, m)
+		resp, err := s.Client.UniversalClient.DoCall(getUniversalInfo(action), &m)
+		if err != nil {
+			return nil, "", err
+		}
+		logger.Debug(logger.RespFormat, action, m, *resp)
+
+		results, err := bp.ObtainSdkValue("Result.ResourceTagMappingList", *resp)
+		if err != nil {
+			return nil, "", err
+		}
+		if results == nil {
+			results = []interface{}{}
+		}
+		list, ok := results.([]interface{})
+		if !ok {
+			return nil, "", errors.New("Result.ResourceTagMappingList is not Slice")
+		}
+		token, _ := bp.ObtainSdkValue("Result.NextToken", *resp)
+		nextToken, _ := token.(string)
+		for _, v := range list {
+			if r, ok := v.(map[string]interface{}); ok {
+				if tags, ok := r["Tags"].([]interface{}); ok {
+					r["Tags"] = bp.FilterSystemTags(tags)
+				}
+			}
+		}
+		return list, nextToken, nil
+	})
+}
+
+func (s *VestackResourcesByTagService) ReadResource(resourceData *schema.ResourceData, id string) (data map[string]interface{}, err error) {
+	return nil, nil
+}
+
+func (s *VestackResourcesByTagService) RefreshResourceState(resourceData *schema.ResourceData, target []string, timeout time.Duration, id string) *resource.StateChangeConf {
+	return nil
+}
+
+func (s *VestackResourcesByTagService) WithResourceResponseHandlers(m map[string]interface{}) []bp.ResourceResponseHandler {
+	handler := func() (map[string]interface{}, map[string]bp.ResponseConvert, error) {
+		return m, nil, nil
+	}
+	return []bp.ResourceResponseHandler{handler}
+}
+
+func (s *VestackResourcesByTagService) CreateResource(resourceData *schema.ResourceData, resource *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackResourcesByTagService) ModifyResource(resourceData *schema.ResourceData, resource *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackResourcesByTagService) RemoveResource(resourceData *schema.ResourceData, r *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackResourcesByTagService) DatasourceResources(*schema.ResourceData, *schema.Resource) bp.DataSourceInfo {
+	return bp.DataSourceInfo{
+		RequestConverts: map[string]bp.RequestConvert{
+			"resource_types": {
+				TargetField: "ResourceTypes",
+				ConvertType: bp.ConvertJsonArray,
+			},
+			"tags": {
+				TargetField: "TagFilters",
+				ConvertType: bp.ConvertJsonObjectArray,
+			},
+		},
+		ContentType:  bp.ContentTypeJson,
+		IdField:      "Trn",
+		CollectField: "resources",
+	}
+}
+
+func (s *VestackResourcesByTagService) ReadResourceId(id string) string {
+	return id
+}
+
+func getUniversalInfo(actionName string) bp.UniversalInfo {
+	return bp.UniversalInfo{
+		ServiceName: "tag",
+		Version:     "2018-01-01",
+		HttpMethod:  bp.POST,
+		ContentType: bp.ApplicationJSON,
+		Action:      actionName,
+	}
+}
@@ -0,0 +1,58 @@
+package resources_by_tag
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func Test_ReadResources(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("GetResources",
+		bp.MockResponse{Result: map[string]interface{}{
+			"NextToken": "token-1",
+			"ResourceTagMappingList": []interface{}{
+				map[string]interface{}{"Trn": "trn:vpc:cn-mock:1:vpc/vpc-1", "ResourceId": "vpc-1", "ResourceType": "vpc",
+					"ServiceName": "vpc", "Region": "cn-mock", "Tags": []interface{}{
+						map[string]interface{}{"Key": "env", "Value": "dev"},
+						map[string]interface{}{"Key": "volc:created-by", "Value": "vke"},
+					}},
+			},
+		}},
+		bp.MockResponse{Result: map[string]interface{}{
+			"ResourceTagMappingList": []interface{}{
+				map[string]interface{}{"Trn": "trn:ecs:cn-mock:1:instance/i-1", "ResourceId": "i-1", "ResourceType": "instance",
+					"ServiceName": "ecs", "Region": "cn-mock", "Tags": []interface{}{
+						map[string]interface{}{"Key": "env", "Value": "dev"},
+					}},
+			},
+		}},
+	)
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	r := DataSourceVestackResourcesByTag()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"resource_types": []interface{}{"vpc:vpc"},
+		"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "dev"},
+		},
+	})
+	assert.Nil(t, bp.DefaultDispatcher().Data(NewResourcesByTagService(client), d, r))
+	assert.Equal(t, 2, d.Get("total_count"))
+	assert.Equal(t, "trn:vpc:cn-mock:1:vpc/vpc-1", d.Get("resources.0.trn"))
+	assert.Equal(t, "ecs", d.Get("resources.1.service_name"))
+	assert.Equal(t, 1, d.Get("resources.0.tags.#"))
+
+	requests := server.Requests("GetResources")
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "tag", requests[0].Service)
+	assert.Equal(t, []interface{}{"vpc:vpc"}, requests[0].Params["ResourceTypes"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"Key": "env", "Values": []interface{}{"dev"}},
+	}, requests[0].Params["TagFilters"])
+	assert.Equal(t, "token-1", requests[1].Params["NextToken"])
+}
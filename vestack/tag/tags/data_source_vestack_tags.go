@@ -0,0 +1,78 @@
+package tags
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func DataSourceVestackTags() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVestackTagsRead,
+		Schema: map[string]*schema.Schema{
+			"resource_types": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(resourceTypes(), false),
+				},
+				Set:         schema.HashString,
+				Description: "A list of resource types, such as `vestack_vpc`. Default is all taggable resource types.",
+			},
+			"resource_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of resource ids. The name of user and role for `vestack_iam_user` and `vestack_iam_role`.",
+			},
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results.",
+			},
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The total count of tag query.",
+			},
+			"tags": {
+				Description: "The collection of tag query.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The resource type of terraform.",
+						},
+						"resource_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the resource.",
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The key of the tag.",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The value of the tag.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVestackTagsRead(d *schema.ResourceData, meta interface{}) error {
+	tagService := NewTagService(meta.(*bp.SdkClient))
+	return bp.DefaultDispatcher().Data(tagService, d, DataSourceVestackTags())
+}
@@ -0,0 +1,171 @@
+package tags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/vestack/clb/certificate"
+	"github.com/volcengine/terraform-provider-vestack/vestack/clb/clb"
+	"github.com/volcengine/terraform-provider-vestack/vestack/clb/server_group"
+	"github.com/volcengine/terraform-provider-vestack/vestack/direct_connect/direct_connect_connection"
+	"github.com/volcengine/terraform-provider-vestack/vestack/direct_connect/direct_connect_gateway"
+	"github.com/volcengine/terraform-provider-vestack/vestack/ebs/volume"
+	"github.com/volcengine/terraform-provider-vestack/vestack/ecs/ecs_instance"
+	"github.com/volcengine/terraform-provider-vestack/vestack/eip/eip_address"
+	"github.com/volcengine/terraform-provider-vestack/vestack/iam/iam_role"
+	"github.com/volcengine/terraform-provider-vestack/vestack/iam/iam_user"
+	"github.com/volcengine/terraform-provider-vestack/vestack/nat/nat_gateway"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke/cluster"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke/node_pool"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/network_acl"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/network_interface"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/route_table"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/security_group"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/subnet"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/traffic_mirror_filter"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/traffic_mirror_session"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/traffic_mirror_target"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/vpc"
+)
+
+// taggedResources the services of every taggable resource, keyed by resource name of terraform.
+// TagInfo of the services never uses the client
+var taggedResources = map[string]bp.TagEnabled{
+	"vestack_vpc":                       &vpc.VestackVpcService{},
+	"vestack_subnet":                    &subnet.VestackSubnetService{},
+	"vestack_route_table":               &route_table.VestackRouteTableService{},
+	"vestack_network_acl":               &network_acl.VestackNetworkAclService{},
+	"vestack_security_group":            &security_group.VestackSecurityGroupService{},
+	"vestack_network_interface":         &network_interface.VestackNetworkInterfaceService{},
+	"vestack_traffic_mirror_filter":     &traffic_mirror_filter.VestackTrafficMirrorFilterService{},
+	"vestack_traffic_mirror_session":    &traffic_mirror_session.VestackTrafficMirrorSessionService{},
+	"vestack_traffic_mirror_target":     &traffic_mirror_target.VestackTrafficMirrorTargetService{},
+	"vestack_eip_address":               &eip_address.VestackEipAddressService{},
+	"vestack_nat_gateway":               &nat_gateway.VestackNatGatewayService{},
+	"vestack_clb":                       &clb.VestackClbService{},
+	"vestack_server_group":              &server_group.VestackServerGroupService{},
+	"vestack_certificate":               &certificate.VestackCertificateService{},
+	"vestack_volume":                    &volume.VestackVolumeService{},
+	"vestack_ecs_instance":              &ecs_instance.VestackEcsService{},
+	"vestack_vke_cluster":               &cluster.VestackVkeClusterService{},
+	"vestack_vke_node_pool":             &node_pool.VestackNodePoolService{},
+	"vestack_iam_user":                  &iam_user.VestackIamUserService{},
+	"vestack_iam_role":                  &iam_role.VestackIamRoleService{},
+	"vestack_direct_connect_connection": &direct_connect_connection.VestackDirectConnectConnectionService{},
+	"vestack_direct_connect_gateway":    &direct_connect_gateway.VestackDirectConnectGatewayService{},
+}
+
+func resourceTypes() []string {
+	var types []string
+	for k := range taggedResources {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+	return types
+}
+
+type VestackTagService struct {
+	Client *bp.SdkClient
+}
+
+func NewTagService(c *bp.SdkClient) *VestackTagService {
+	return &VestackTagService{
+		Client: c,
+	}
+}
+
+func (s *VestackTagService) GetClient() *bp.SdkClient {
+	return s.Client
+}
+
+func (s *VestackTagService) ReadResources(condition map[string]interface{}) (data []interface{}, err error) {
+	var (
+		types []string
+		ids   []string
+	)
+	for k, v := range condition {
+		if strings.HasPrefix(k, "ResourceTypes.") {
+			types = append(types, v.(string))
+		}
+		if strings.HasPrefix(k, "ResourceIds.") {
+			ids = append(ids, v.(string))
+		}
+	}
+	if len(types) == 0 {
+		types = resourceTypes()
+	}
+	sort.Strings(types)
+	sort.Strings(ids)
+
+	tagService := bp.NewTagService(s.Client)
+	for _, t := range types {
+		service, ok := taggedResources[t]
+		if !ok {
+			return data, fmt.Errorf("resource type %s does not support tags", t)
+		}
+		tags, err := tagService.ListTags(service.TagInfo(), ids)
+		if err != nil {
+			return data, err
+		}
+		for _, tag := range bp.FilterSystemTags(tags) {
+			m := tag.(map[string]interface{})
+			m["ResourceType"] = t
+			m["TagId"] = fmt.Sprintf("%s:%v:%v", t, m["ResourceId"], m["Key"])
+			data = append(data, m)
+		}
+	}
+	return data, nil
+}
+
+func (s *VestackTagService) ReadResource(resourceData *schema.ResourceData, id string) (data map[string]interface{}, err error) {
+	return nil, nil
+}
+
+func (s *VestackTagService) RefreshResourceState(resourceData *schema.ResourceData, target []string, timeout time.Duration, id string) *resource.StateChangeConf {
+	return nil
+}
+
+func (s *VestackTagService) WithResourceResponseHandlers(tag map[string]interface{}) []bp.ResourceResponseHandler {
+	handler := func() (map[string]interface{}, map[string]bp.ResponseConvert, error) {
+		return tag, nil, nil
+	}
+	return []bp.ResourceResponseHandler{handler}
+}
+
+func (s *VestackTagService) CreateResource(resourceData *schema.ResourceData, resource *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackTagService) ModifyResource(resourceData *schema.ResourceData, resource *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackTagService) RemoveResource(resourceData *schema.ResourceData, r *schema.Resource) []bp.Callback {
+	return []bp.Callback{}
+}
+
+func (s *VestackTagService) DatasourceResources(*schema.ResourceData, *schema.Resource) bp.DataSourceInfo {
+	return bp.DataSourceInfo{
+		RequestConverts: map[string]bp.RequestConvert{
+			"resource_types": {
+				TargetField: "ResourceTypes",
+				ConvertType: bp.ConvertWithN,
+			},
+			"resource_ids": {
+				TargetField: "ResourceIds",
+				ConvertType: bp.ConvertWithN,
+			},
+		},
+		IdField:      "TagId",
+		CollectField: "tags",
+	}
+}
+
+func (s *VestackTagService) ReadResourceId(id string) string {
+	return id
+}
@@ -2,7 +2,6 @@ package object
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -20,6 +19,8 @@ $ terraform import vestack_tos_object.default bucketName:objectName
 
 */
 
+var tosObjectCompositeId = bp.NewCompositeId("bucket_name", "object_name")
+
 func ResourceVestackTosObject() *schema.Resource {
 	resource := &schema.Resource{
 		Create: resourceVestackTosObjectCreate,
@@ -31,17 +32,7 @@ func ResourceVestackTosObject() *schema.Resource {
 			Update: schema.DefaultTimeout(2 * time.Minute),
 			Delete: schema.DefaultTimeout(2 * time.Minute),
 		},
-		Importer: &schema.ResourceImporter{
-			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
-				items := strings.Split(data.Id(), ":")
-				if len(items) != 2 {
-					return []*schema.ResourceData{data}, fmt.Errorf("import id must be of the form bucketName:objectName")
-				}
-				_ = data.Set("bucket_name", items[0])
-				_ = data.Set("object_name", items[1])
-				return []*schema.ResourceData{data}, nil
-			},
-		},
+		Importer: tosObjectCompositeId.Importer(),
 		CustomizeDiff: func(diff *schema.ResourceDiff, i interface{}) (err error) {
 			if diff.Id() != "" && diff.HasChange("file_path") && !diff.Get("enable_version").(bool) {
 				return diff.ForceNew("file_path")
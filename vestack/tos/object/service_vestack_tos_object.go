@@ -54,8 +54,9 @@ func (s *VestackTosObjectService) ReadResources(condition map[string]interface{}
 
 func (s *VestackTosObjectService) ReadResource(resourceData *schema.ResourceData, instanceId string) (data map[string]interface{}, err error) {
 	tos := s.Client.BypassSvcClient
-	bucketName := resourceData.Get("bucket_name").(string)
 	var (
+		ids           []string
+		bucketName    string
 		action        string
 		resp          *map[string]interface{}
 		respBody      *map[string]interface{}
@@ -66,13 +67,15 @@ func (s *VestackTosObjectService) ReadResource(resourceData *schema.ResourceData
 	)
 
 	if instanceId == "" {
-		instanceId = s.ReadResourceId(resourceData.Id())
-	} else {
-		instanceId = s.ReadResourceId(instanceId)
+		instanceId = resourceData.Id()
+	}
+	if ids, err = tosObjectCompositeId.Decode(instanceId); err != nil {
+		return data, err
 	}
+	bucketName, instanceId = ids[0], ids[1]
 
 	action = "HeadObject"
-	logger.Debug(logger.ReqFormat, action, bucketName+":"+instanceId)
+	logger.Debug(logger.ReqFormat, action, ids)
 	resp, err = tos.DoBypassSvcCall(bp.BypassSvcInfo{
 		HttpMethod: bp.HEAD,
 		Domain:     bucketName,
@@ -93,7 +96,7 @@ func (s *VestackTosObjectService) ReadResource(resourceData *schema.ResourceData
 				strings.Contains(strings.ToLower(header.Get("Content-Type")), "application/xml") ||
 				strings.Contains(strings.ToLower(header.Get("Content-Type")), "text/plain") {
 				action = "GetObject"
-				logger.Debug(logger.ReqFormat, action, bucketName+":"+instanceId)
+				logger.Debug(logger.ReqFormat, action, ids)
 				respBody, err = tos.DoBypassSvcCall(bp.BypassSvcInfo{
 					HttpMethod: bp.GET,
 					Domain:     bucketName,
@@ -114,7 +117,7 @@ func (s *VestackTosObjectService) ReadResource(resourceData *schema.ResourceData
 
 		if header.Get("X-Tos-Version-Id") != "" {
 			action = "ListObjects"
-			logger.Debug(logger.ReqFormat, action, bucketName+":"+instanceId)
+			logger.Debug(logger.ReqFormat, action, ids)
 
 			var (
 				nextVersionIdMarker string
@@ -358,19 +361,17 @@ func (s *VestackTosObjectService) DatasourceResources(data *schema.ResourceData,
 		},
 		ExtraData: func(sourceData []interface{}) (extraData []interface{}, err error) {
 			for _, v := range sourceData {
+				if ok && name.(string) != v.(map[string]interface{})["Key"].(string) {
+					continue
+				}
+				v.(map[string]interface{})["ObjectId"], err = tosObjectCompositeId.Encode(bucketName.(string), v.(map[string]interface{})["Key"].(string))
+				if err != nil {
+					return nil, err
+				}
+				extraData = append(extraData, v)
 				if ok {
-					if name.(string) == v.(map[string]interface{})["Key"].(string) {
-						v.(map[string]interface{})["ObjectId"] = bucketName.(string) + ":" + v.(map[string]interface{})["Key"].(string)
-						extraData = append(extraData, v)
-						break
-					} else {
-						continue
-					}
-				} else {
-					v.(map[string]interface{})["ObjectId"] = bucketName.(string) + ":" + v.(map[string]interface{})["Key"].(string)
-					extraData = append(extraData, v)
+					break
 				}
-
 			}
 			return extraData, err
 		},
@@ -379,16 +380,16 @@ func (s *VestackTosObjectService) DatasourceResources(data *schema.ResourceData,
 			var newSourceData []interface{}
 			for _, v := range sourceData {
 				var (
-					key     interface{}
+					id      interface{}
 					newData map[string]interface{}
 					err     error
 				)
-				key, err = bp.ObtainSdkValue("Key", v)
+				id, err = bp.ObtainSdkValue("ObjectId", v)
 				if err != nil {
 					return nil, err
 				}
 
-				if str, ok1 := key.(string); ok1 {
+				if str, ok1 := id.(string); ok1 {
 					newData, err = s.ReadResource(d, str)
 					if err != nil {
 						return nil, err
@@ -409,8 +410,13 @@ func (s *VestackTosObjectService) DatasourceResources(data *schema.ResourceData,
 	}
 }
 
+// ReadResourceId the object name of the id
 func (s *VestackTosObjectService) ReadResourceId(id string) string {
-	return id[strings.Index(id, ":")+1:]
+	ids, err := tosObjectCompositeId.Decode(id)
+	if err != nil {
+		return id
+	}
+	return ids[1]
 }
 
 func (s *VestackTosObjectService) beforePutObjectAcl() bp.BeforeCallFunc {
@@ -605,7 +611,11 @@ func (s *VestackTosObjectService) createOrReplaceObject(resourceData *schema.Res
 				}, nil)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId((*call.SdkParam)[bp.BypassDomain].(string) + ":" + (*call.SdkParam)[bp.BypassPath].([]string)[0])
+				id, err := tosObjectCompositeId.Encode((*call.SdkParam)[bp.BypassDomain].(string), (*call.SdkParam)[bp.BypassPath].([]string)[0])
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 		},
@@ -0,0 +1,39 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func Test_ReadResource_CompositeId(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("HEAD /dir/a:b.txt", bp.MockResponse{Header: map[string]string{
+		"X-Tos-Storage-Class": "STANDARD",
+		"Content-Type":        "application/octet-stream",
+	}})
+	server.On("GET /dir/a:b.txt", bp.MockResponse{Body: `{"Grants":[]}`})
+	server.On("GET /", bp.MockResponse{Body: `{"Status":"Enabled"}`})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewTosObjectService(client)
+	d := schema.TestResourceDataRaw(t, ResourceVestackTosObject().Schema, map[string]interface{}{})
+	d.SetId("bucket-1:dir/a:b.txt")
+
+	assert.Equal(t, "dir/a:b.txt", svc.ReadResourceId(d.Id()))
+	data, err := svc.ReadResource(d, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "STANDARD", data["StorageClass"])
+	requests := server.Requests("HEAD /dir/a:b.txt")
+	if assert.Equal(t, 1, len(requests)) {
+		assert.Contains(t, requests[0].Host, "bucket-1")
+	}
+
+	_, err = svc.ReadResource(d, "dir/a.txt")
+	assert.NotNil(t, err)
+}
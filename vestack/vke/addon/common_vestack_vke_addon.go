@@ -0,0 +1,368 @@
+package addon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke/support_addon"
+	"gopkg.in/yaml.v3"
+)
+
+// configImmutableAddons the addons prohibit updating config, their config can only be updated on the web console
+var configImmutableAddons = map[string]bool{
+	"ingress-nginx": true,
+}
+
+// decodeAddonConfig decode the JSON or YAML config into the values of JSON
+func decodeAddonConfig(config string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(config), &v); err == nil {
+		return v, nil
+	}
+	if err := yaml.Unmarshal([]byte(config), &v); err != nil {
+		return nil, fmt.Errorf("config is neither JSON nor YAML: %w", err)
+	}
+	v, err := yamlToJson(v)
+	if err != nil {
+		return nil, err
+	}
+	// round trip so the numbers are float64 as the JSON config
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+func yamlToJson(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			item, err := yamlToJson(item)
+			if err != nil {
+				return nil, err
+			}
+			value[k] = item
+		}
+		return value, nil
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			item, err := yamlToJson(item)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(k)] = item
+		}
+		return result, nil
+	case []interface{}:
+		for i, item := range value {
+			item, err := yamlToJson(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = item
+		}
+		return value, nil
+	}
+	return v, nil
+}
+
+// normalizeAddonConfig the canonical JSON of the config, the keys of objects are sorted
+func normalizeAddonConfig(config string) (string, error) {
+	if strings.TrimSpace(config) == "" {
+		return "", nil
+	}
+	v, err := decodeAddonConfig(config)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// jsonAddonConfigParam the api only accepts JSON config, a YAML Config of param is sent as the canonical JSON
+func jsonAddonConfigParam(param *map[string]interface{}) error {
+	config, ok := (*param)["Config"].(string)
+	if !ok || json.Valid([]byte(config)) {
+		return nil
+	}
+	config, err := normalizeAddonConfig(config)
+	if err != nil {
+		return err
+	}
+	(*param)["Config"] = config
+	return nil
+}
+
+// addonConfigDiffSuppress the configs with the same canonical JSON have no diff, e.g. reformatted JSON or YAML
+var addonConfigDiffSuppress = func(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	o, err := normalizeAddonConfig(old)
+	if err != nil {
+		return false
+	}
+	n, err := normalizeAddonConfig(new)
+	if err != nil {
+		return false
+	}
+	return o == n
+}
+
+// addonConfigChanged HasChange of ResourceDiff is true for the suppressed diff, so the canonical configs are compared
+func addonConfigChanged(diff *schema.ResourceDiff) bool {
+	if !diff.HasChange("config") {
+		return false
+	}
+	o, n := diff.GetChange("config")
+	return !addonConfigDiffSuppress("config", o.(string), n.(string), nil)
+}
+
+// validateAddonConfig the config must be a JSON object or a YAML mapping
+var validateAddonConfig = func(i interface{}, k string) (warnings []string, errors []error) {
+	config, _ := i.(string)
+	if strings.TrimSpace(config) == "" {
+		return
+	}
+	v, err := decodeAddonConfig(config)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q is invalid: %s", k, err))
+		return
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		errors = append(errors, fmt.Errorf("%q must be a JSON object or a YAML mapping", k))
+	}
+	return
+}
+
+// addonConfigCustomizeDiff validate the config against the config schema of the addon version at plan time
+var addonConfigCustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("config") || !diff.NewValueKnown("name") {
+		return nil
+	}
+	if diff.Id() != "" && !addonConfigChanged(diff) && !diff.HasChange("version") {
+		return nil
+	}
+	config, _ := diff.Get("config").(string)
+	if strings.TrimSpace(config) == "" {
+		return nil
+	}
+	client, ok := meta.(*bp.SdkClient)
+	if !ok || client == nil {
+		return nil
+	}
+	// the version is defaulted by the api when not set, the config is validated by the api on apply
+	if !diff.NewValueKnown("version") {
+		return nil
+	}
+	version, _ := diff.Get("version").(string)
+	if version == "" {
+		return nil
+	}
+	name := diff.Get("name").(string)
+	configSchema, err := getAddonConfigSchema(client, name, version)
+	if err != nil {
+		// the config is still validated by the api on apply
+		logger.Warn("get config schema of vke addon %s error: %s", name, err)
+		return nil
+	}
+	if configSchema == nil {
+		return nil
+	}
+	value, err := decodeAddonConfig(config)
+	if err != nil {
+		return err
+	}
+	if errs := validateJsonSchema(configSchema, value, ""); len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("config of vke addon %s is invalid: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// getAddonConfigSchema the ConfigSchema of the version of the supported addon, nil is returned when the version has no
+// config schema
+func getAddonConfigSchema(client *bp.SdkClient, name, version string) (map[string]interface{}, error) {
+	addons, err := support_addon.NewVkeSupportAddonService(client).ReadResources(map[string]interface{}{
+		"Filter": map[string]interface{}{
+			"Name": name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var configSchema string
+	for _, addon := range addons {
+		if n, _ := bp.ObtainSdkValue("Name", addon); n != name {
+			continue
+		}
+		versions, _ := bp.ObtainSdkValue("Versions", addon)
+		list, _ := versions.([]interface{})
+		for _, v := range list {
+			if ver, _ := bp.ObtainSdkValue("Version", v); ver == version {
+				s, _ := bp.ObtainSdkValue("ConfigSchema", v)
+				configSchema, _ = s.(string)
+				break
+			}
+		}
+	}
+	if configSchema == "" {
+		return nil, nil
+	}
+	var result map[string]interface{}
+	if err = json.Unmarshal([]byte(configSchema), &result); err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+	return result, nil
+}
+
+// validateJsonSchema validate value against the common keywords of JSON schema: type, enum, properties, required,
+// additionalProperties, items, minimum, maximum, minLength, maxLength, pattern, minItems and maxItems
+func validateJsonSchema(s map[string]interface{}, value interface{}, path string) []string {
+	var errs []string
+	name := path
+	if name == "" {
+		name = "config"
+	}
+	if t, ok := s["type"]; ok && !matchJsonType(t, value) {
+		return []string{fmt.Sprintf("%s must be %s", name, jsonTypeString(t))}
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s must be one of %v", name, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := s["properties"].(map[string]interface{})
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, r := range required {
+				if _, ok := v[fmt.Sprint(r)]; !ok {
+					errs = append(errs, fmt.Sprintf("%s is required", joinJsonPath(path, fmt.Sprint(r))))
+				}
+			}
+		}
+		for k, item := range v {
+			if p, ok := properties[k].(map[string]interface{}); ok {
+				errs = append(errs, validateJsonSchema(p, item, joinJsonPath(path, k))...)
+				continue
+			}
+			switch additional := s["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Sprintf("%s is not supported", joinJsonPath(path, k)))
+				}
+			case map[string]interface{}:
+				errs = append(errs, validateJsonSchema(additional, item, joinJsonPath(path, k))...)
+			}
+		}
+	case []interface{}:
+		if min, ok := s["minItems"].(float64); ok && float64(len(v)) < min {
+			errs = append(errs, fmt.Sprintf("%s must have at least %v items", name, min))
+		}
+		if max, ok := s["maxItems"].(float64); ok && float64(len(v)) > max {
+			errs = append(errs, fmt.Sprintf("%s must have at most %v items", name, max))
+		}
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateJsonSchema(items, item, fmt.Sprintf("%s[%d]", name, i))...)
+			}
+		}
+	case float64:
+		if min, ok := s["minimum"].(float64); ok && v < min {
+			errs = append(errs, fmt.Sprintf("%s must be at least %v", name, min))
+		}
+		if max, ok := s["maximum"].(float64); ok && v > max {
+			errs = append(errs, fmt.Sprintf("%s must be at most %v", name, max))
+		}
+	case string:
+		if min, ok := s["minLength"].(float64); ok && float64(len(v)) < min {
+			errs = append(errs, fmt.Sprintf("%s must be at least %v characters", name, min))
+		}
+		if max, ok := s["maxLength"].(float64); ok && float64(len(v)) > max {
+			errs = append(errs, fmt.Sprintf("%s must be at most %v characters", name, max))
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				errs = append(errs, fmt.Sprintf("%s must match %s", name, pattern))
+			}
+		}
+	}
+	return errs
+}
+
+func joinJsonPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// matchJsonType the type of JSON schema may be a type or a list of types
+func matchJsonType(t interface{}, value interface{}) bool {
+	types, ok := t.([]interface{})
+	if !ok {
+		types = []interface{}{t}
+	}
+	for _, item := range types {
+		switch item {
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := value.(float64); ok && f == float64(int64(f)) {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonTypeString(t interface{}) string {
+	if types, ok := t.([]interface{}); ok {
+		var s []string
+		for _, item := range types {
+			s = append(s, fmt.Sprint(item))
+		}
+		return strings.Join(s, " or ")
+	}
+	return fmt.Sprint(t)
+}
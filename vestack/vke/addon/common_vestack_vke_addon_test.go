@@ -0,0 +1,209 @@
+package addon
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+const testConfigSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["replicas"],
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1, "maximum": 3},
+    "mode": {"type": "string", "enum": ["Public", "Private"]},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+  }
+}`
+
+func Test_NormalizeAddonConfig(t *testing.T) {
+	for _, c := range []struct {
+		old, new string
+		equal    bool
+	}{
+		{`{"a":1,"b":{"c":true}}`, "{\n  \"b\": {\"c\": true},\n  \"a\": 1\n}", true},
+		{`{"a":1,"b":["x"]}`, "a: 1\nb:\n- x\n", true},
+		{`{"a":1}`, `{"a":2}`, false},
+		{`{"a":1}`, `{"a":1`, false},
+	} {
+		assert.Equal(t, c.equal, addonConfigDiffSuppress("config", c.old, c.new, nil), c.new)
+	}
+
+	for _, c := range []struct {
+		config string
+		valid  bool
+	}{
+		{"", true},
+		{`{"a":1}`, true},
+		{"a: 1", true},
+		{`["a"]`, false},
+		{"plain", false},
+		{"a: [", false},
+	} {
+		_, errs := validateAddonConfig(c.config, "config")
+		assert.Equal(t, c.valid, len(errs) == 0, c.config)
+	}
+}
+
+func Test_ValidateJsonSchema(t *testing.T) {
+	s, err := decodeAddonConfig(testConfigSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configSchema := s.(map[string]interface{})
+	for _, c := range []struct {
+		config string
+		errs   int
+	}{
+		{`{"replicas": 2, "mode": "Public", "labels": {"a": "b"}}`, 0},
+		{"replicas: 1\nmode: Private", 0},
+		{`{"replicas": 1.5}`, 1},
+		{`{"replicas": 5, "mode": "Other"}`, 2},
+		{`{"mode": "Public", "unknown": 1}`, 2},
+		{`{"replicas": 1, "labels": {"a": 1}}`, 1},
+	} {
+		value, err := decodeAddonConfig(c.config)
+		if assert.Nil(t, err) {
+			assert.Equal(t, c.errs, len(validateJsonSchema(configSchema, value, "")), c.config)
+		}
+	}
+}
+
+func Test_AddonConfigCustomizeDiff(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("ListSupportedAddons", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{
+			"Name": "core-dns",
+			"Versions": []interface{}{
+				map[string]interface{}{"Version": "1.0.0", "ConfigSchema": testConfigSchema},
+				map[string]interface{}{"Version": "0.9.0"},
+			},
+		},
+	}}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := ResourceVestackVkeAddon()
+
+	for _, c := range []struct {
+		version string
+		config  string
+		valid   bool
+	}{
+		{"1.0.0", `{"replicas": 2}`, true},
+		{"1.0.0", `{"replicas": 5}`, false},
+		// the version is defaulted by the api
+		{"", `{"replicas": 5}`, true},
+		// the version has no config schema
+		{"0.9.0", `{"replicas": 5}`, true},
+	} {
+		cfg := map[string]interface{}{
+			"cluster_id": "cc-1",
+			"name":       "core-dns",
+			"config":     c.config,
+		}
+		if c.version != "" {
+			cfg["version"] = c.version
+		}
+		_, err = r.Diff(nil, terraform.NewResourceConfigRaw(cfg), client)
+		assert.Equal(t, c.valid, err == nil, c.version+" "+c.config)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "cc-1:ingress-nginx",
+		Attributes: map[string]string{
+			"id":         "cc-1:ingress-nginx",
+			"cluster_id": "cc-1",
+			"name":       "ingress-nginx",
+			"version":    "1.0.0",
+			"config":     `{"replicas":2}`,
+		},
+	}
+	// the reformatted config has no diff
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cluster_id": "cc-1",
+		"name":       "ingress-nginx",
+		"config":     "{\n  \"replicas\": 2\n}",
+	}), client)
+	if assert.Nil(t, err) && diff != nil {
+		assert.Empty(t, diff.Attributes)
+	}
+	_, err = r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cluster_id": "cc-1",
+		"name":       "ingress-nginx",
+		"config":     `{"replicas": 3}`,
+	}), client)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "ingress-nginx addon prohibits updating config")
+	}
+}
+
+func Test_AddonConfigRequest(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("CreateAddon", bp.MockResponse{Result: map[string]interface{}{}})
+	server.On("UpdateAddonConfig", bp.MockResponse{Result: map[string]interface{}{}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewVkeAddonService(client)
+	r := ResourceVestackVkeAddon()
+
+	// the YAML config is sent as JSON
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"cluster_id": "cc-1",
+		"name":       "core-dns",
+		"config":     "replicas: 2\nmode: Public\n",
+	})
+	call := svc.CreateResource(d, r)[0].Call
+	call.Refresh = nil
+	assert.Nil(t, call.InitWriteCall(d, r, false))
+	assert.Nil(t, bp.CallProcess([]bp.SdkCall{call}, d, client, svc))
+	assert.Equal(t, `{"mode":"Public","replicas":2}`, server.Requests("CreateAddon")[0].Params["Config"])
+
+	// the JSON config is sent as it is
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"cluster_id": "cc-1",
+		"name":       "core-dns",
+		"config":     `{"replicas": 3}`,
+	})
+	call = svc.CreateResource(d, r)[0].Call
+	call.Refresh = nil
+	assert.Nil(t, call.InitWriteCall(d, r, false))
+	assert.Nil(t, bp.CallProcess([]bp.SdkCall{call}, d, client, svc))
+	assert.Equal(t, `{"replicas": 3}`, server.Requests("CreateAddon")[1].Params["Config"])
+
+	state := &terraform.InstanceState{
+		ID: "cc-1:core-dns",
+		Attributes: map[string]string{
+			"id":         "cc-1:core-dns",
+			"cluster_id": "cc-1",
+			"name":       "core-dns",
+			"config":     `{"replicas": 3}`,
+		},
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cluster_id": "cc-1",
+		"name":       "core-dns",
+		"config":     "replicas: 1\n",
+	}), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call = svc.ModifyResource(d, r)[0].Call
+	call.Refresh = nil
+	assert.Nil(t, call.InitWriteCall(d, r, true))
+	assert.Nil(t, bp.CallProcess([]bp.SdkCall{call}, d, client, svc))
+	assert.Equal(t, `{"replicas":1}`, server.Requests("UpdateAddonConfig")[0].Params["Config"])
+}
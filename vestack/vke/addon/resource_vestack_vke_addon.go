@@ -27,11 +27,12 @@ $ terraform state rm vestack_vke_addon.${name}
 */
 
 func ResourceVestackVkeAddon() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceVestackVkeAddonCreate,
-		Read:   resourceVestackVkeAddonRead,
-		Update: resourceVestackVkeAddonUpdate,
-		Delete: resourceVestackVkeAddonDelete,
+	resource := &schema.Resource{
+		Create:        resourceVestackVkeAddonCreate,
+		Read:          resourceVestackVkeAddonRead,
+		Update:        resourceVestackVkeAddonUpdate,
+		Delete:        resourceVestackVkeAddonDelete,
+		CustomizeDiff: addonConfigCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
 				items := strings.Split(data.Id(), ":")
@@ -87,14 +88,18 @@ func ResourceVestackVkeAddon() *schema.Resource {
 				Description: "The deploy node type.",
 			},
 			"config": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				Description: "The config info of addon. " +
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateAddonConfig,
+				DiffSuppressFunc: addonConfigDiffSuppress,
+				Description: "The config info of addon, a JSON object or a YAML mapping, the YAML config is sent to the api as JSON. The configs with the same content are not treated as changes, " +
+					"e.g. the reformatted JSON. The config is validated against the `config_schema` of the addon version in `vestack_vke_support_addons` at plan time when the `version` is set. " +
 					"Please notice that `ingress-nginx` component prohibits updating config, can only works on the web console.",
 			},
 		},
 	}
+	return bp.ResourceDiffConstraints(resource, &VestackVkeAddonService{})
 }
 
 func resourceVestackVkeAddonCreate(d *schema.ResourceData, meta interface{}) (err error) {
@@ -152,6 +152,9 @@ func (s *VestackVkeAddonService) CreateResource(resourceData *schema.ResourceDat
 		Call: bp.SdkCall{
 			Action:      "CreateAddon",
 			ContentType: bp.ContentTypeJson,
+			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
+				return true, jsonAddonConfigParam(call.SdkParam)
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
 				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
@@ -185,11 +188,11 @@ func (s *VestackVkeAddonService) ModifyResource(resourceData *schema.ResourceDat
 				(*call.SdkParam)["ClusterId"] = ids[0]
 				(*call.SdkParam)["Name"] = ids[1]
 
-				if ids[1] == "ingress-nginx" {
-					return false, fmt.Errorf("ingress-nginx addon prohibits updating config")
+				if configImmutableAddons[ids[1]] {
+					return false, fmt.Errorf("%s addon prohibits updating config", ids[1])
 				}
 
-				return true, nil
+				return true, jsonAddonConfigParam(call.SdkParam)
 			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				req, err := json.Marshal(*call.SdkParam)
@@ -310,6 +313,21 @@ func (s *VestackVkeAddonService) ReadResourceId(id string) string {
 	return id
 }
 
+func (s *VestackVkeAddonService) DiffConstraints() []bp.DiffConstraint {
+	return []bp.DiffConstraint{
+		{
+			Fields: []string{"config"},
+			Check: func(diff *schema.ResourceDiff) error {
+				name, _ := diff.Get("name").(string)
+				if diff.Id() == "" || !configImmutableAddons[name] || !addonConfigChanged(diff) {
+					return nil
+				}
+				return fmt.Errorf("%s addon prohibits updating config, it can only be updated on the web console", name)
+			},
+		},
+	}
+}
+
 func getUniversalInfo(actionName string) bp.UniversalInfo {
 	return bp.UniversalInfo{
 		ServiceName: "vke",
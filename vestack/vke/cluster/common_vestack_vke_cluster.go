@@ -61,3 +61,14 @@ var (
 		"Starting": true,
 	}
 )
+
+// upgradeNodePoolsCustomizeDiff plan an update to resume the interrupted upgrade of the node pools
+var upgradeNodePoolsCustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || !diff.Get("upgrade_node_pools").(bool) {
+		return nil
+	}
+	if pending, ok := diff.Get("upgrade_pending_node_ids").(*schema.Set); ok && pending.Len() > 0 {
+		return diff.SetNewComputed("upgrade_pending_node_ids")
+	}
+	return nil
+}
@@ -23,11 +23,12 @@ $ terraform import vestack_vke_cluster.default cc9l74mvqtofjnoj5****
 */
 
 func ResourceVestackVkeCluster() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceVestackVkeClusterCreate,
-		Read:   resourceVestackVkeClusterRead,
-		Update: resourceVestackVkeClusterUpdate,
-		Delete: resourceVestackVkeClusterDelete,
+	resource := &schema.Resource{
+		Create:        resourceVestackVkeClusterCreate,
+		Read:          resourceVestackVkeClusterRead,
+		Update:        resourceVestackVkeClusterUpdate,
+		Delete:        resourceVestackVkeClusterDelete,
+		CustomizeDiff: upgradeNodePoolsCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -66,14 +67,36 @@ func ResourceVestackVkeCluster() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
-				ForceNew: true,
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					if k == "kubernetes_version" && strings.Contains(old, new) {
 						return true
 					}
 					return false
 				},
-				Description: "The version of Kubernetes specified when creating a VKE cluster (specified to patch version), if not specified, the latest Kubernetes version supported by VKE is used by default, which is a 3-segment version format starting with a lowercase v, that is, KubernetesVersion with IsLatestVersion=True in the return value of ListSupportedVersions.",
+				Description: "The version of Kubernetes specified when creating a VKE cluster (specified to patch version), if not specified, the latest Kubernetes version supported by VKE is used by default, which is a 3-segment version format starting with a lowercase v, that is, KubernetesVersion with IsLatestVersion=True in the return value of ListSupportedVersions. " +
+					"Modifying this field upgrades the control plane in place, one minor version at a time, downgrade is not supported.",
+			},
+			"upgrade_node_pools": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to upgrade the nodes of all node pools to the kubernetes_version after the control plane is upgraded. Default is false.",
+			},
+			"node_upgrade_max_unavailable": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The max number of nodes of a node pool upgraded at a time when upgrade_node_pools is true. Default is 1.",
+			},
+			"upgrade_pending_node_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Set:      schema.HashString,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The ids of the nodes not upgraded yet by an interrupted upgrade of the node pools, they are upgraded in the next apply.",
 			},
 			"project_name": {
 				Type:        schema.TypeString,
@@ -455,7 +478,7 @@ func ResourceVestackVkeCluster() *schema.Resource {
 							MaxItems:         1,
 							ForceNew:         true,
 							Optional:         true,
-							Description:      "Flannel network configuration.",
+							Description:      "Flannel network configuration. It is required when pod_network_mode is `Flannel`.",
 							DiffSuppressFunc: FlannelFieldDiffSuppress,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
@@ -482,7 +505,7 @@ func ResourceVestackVkeCluster() *schema.Resource {
 							Type:             schema.TypeList,
 							MaxItems:         1,
 							Optional:         true,
-							Description:      "VPC-CNI network configuration.",
+							Description:      "VPC-CNI network configuration. It is required when pod_network_mode is `VpcCniShared` or `VpcCniHybrid`.",
 							DiffSuppressFunc: VpcCniConfigFieldDiffSuppress,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
@@ -680,6 +703,7 @@ func ResourceVestackVkeCluster() *schema.Resource {
 			},
 		},
 	}
+	return bp.ResourceDiffConstraints(resource, &VestackVkeClusterService{})
 }
 
 func resourceVestackVkeClusterCreate(d *schema.ResourceData, meta interface{}) (err error) {
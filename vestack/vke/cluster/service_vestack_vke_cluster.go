@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -419,6 +422,15 @@ func (s *VestackVkeClusterService) CreateResource(resourceData *schema.ResourceD
 			Action:      "CreateCluster",
 			ContentType: bp.ContentTypeJson,
 			Convert: map[string]bp.RequestConvert{
+				"upgrade_node_pools": {
+					Ignore: true,
+				},
+				"node_upgrade_max_unavailable": {
+					Ignore: true,
+				},
+				"upgrade_pending_node_ids": {
+					Ignore: true,
+				},
 				"cluster_config": {
 					ConvertType: bp.ConvertJsonObject,
 					NextLevelConvert: map[string]bp.RequestConvert{
@@ -590,6 +602,18 @@ func (s *VestackVkeClusterService) ModifyResource(resourceData *schema.ResourceD
 			Action:      "UpdateClusterConfig",
 			ContentType: bp.ContentTypeJson,
 			Convert: map[string]bp.RequestConvert{
+				"kubernetes_version": {
+					Ignore: true,
+				},
+				"upgrade_node_pools": {
+					Ignore: true,
+				},
+				"node_upgrade_max_unavailable": {
+					Ignore: true,
+				},
+				"upgrade_pending_node_ids": {
+					Ignore: true,
+				},
 				"cluster_config": {
 					ConvertType: bp.ConvertJsonObject,
 					NextLevelConvert: map[string]bp.RequestConvert{
@@ -755,6 +779,13 @@ func (s *VestackVkeClusterService) ModifyResource(resourceData *schema.ResourceD
 	}
 	callbacks = append(callbacks, callback)
 
+	pending, _ := resourceData.GetChange("upgrade_pending_node_ids")
+	if resourceData.HasChange("kubernetes_version") {
+		callbacks = append(callbacks, s.upgradeCallback(resourceData))
+	} else if resourceData.Get("upgrade_node_pools").(bool) && pending.(*schema.Set).Len() > 0 {
+		callbacks = append(callbacks, s.resumeUpgradeCallback(resourceData))
+	}
+
 	if resourceData.HasChange("cluster_config.0.api_server_public_access_config.0.public_access_network_config.0.bandwidth") &&
 		!resourceData.HasChange("cluster_config.0.api_server_public_access_enabled") {
 		// enable public access, vke will create eip automatic
@@ -785,9 +816,6 @@ func (s *VestackVkeClusterService) ModifyResource(resourceData *schema.ResourceD
 		callbacks = append(callbacks, modifyEipCallback)
 	}
 
-	// 更新Tags
-	callbacks = s.setResourceTags(resourceData, "Cluster", callbacks)
-
 	return callbacks
 }
 
@@ -916,60 +944,6 @@ func (s *VestackVkeClusterService) ReadResourceId(id string) string {
 	return id
 }
 
-func (s *VestackVkeClusterService) setResourceTags(resourceData *schema.ResourceData, resourceType string, callbacks []bp.Callback) []bp.Callback {
-	addedTags, removedTags, _, _ := bp.GetSetDifference("tags", resourceData, bp.TagsHash, false)
-
-	removeCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "UntagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if removedTags != nil && len(removedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["TagKeys"] = make([]string, 0)
-					for _, tag := range removedTags.List() {
-						(*call.SdkParam)["TagKeys"] = append((*call.SdkParam)["TagKeys"].([]string), tag.(map[string]interface{})["key"].(string))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
-	}
-	callbacks = append(callbacks, removeCallback)
-
-	addCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "TagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if addedTags != nil && len(addedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["Tags"] = make([]map[string]interface{}, 0)
-					for _, tag := range addedTags.List() {
-						(*call.SdkParam)["Tags"] = append((*call.SdkParam)["Tags"].([]map[string]interface{}), tag.(map[string]interface{}))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
-	}
-	callbacks = append(callbacks, addCallback)
-
-	return callbacks
-}
-
 func (s *VestackVkeClusterService) ProjectTrn() *bp.ProjectTrn {
 	return &bp.ProjectTrn{
 		ServiceName:          "vke",
@@ -1074,3 +1048,309 @@ func removeSystemTags(data []interface{}) ([]interface{}, error) {
 	}
 	return results, nil
 }
+
+func (s *VestackVkeClusterService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "Cluster",
+		Flavor:         bp.TagFlavorJson,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
+
+func (s *VestackVkeClusterService) DiffConstraints() []bp.DiffConstraint {
+	return []bp.DiffConstraint{
+		bp.When("pods_config.0.pod_network_mode", "Flannel").Required("pods_config.0.flannel_config"),
+		bp.When("pods_config.0.pod_network_mode", "VpcCniShared", "VpcCniHybrid").Required("pods_config.0.vpc_cni_config"),
+		{
+			Fields: []string{"kubernetes_version"},
+			Check: func(diff *schema.ResourceDiff) error {
+				if diff.Id() == "" || !diff.NewValueKnown("kubernetes_version") {
+					return nil
+				}
+				o, n := diff.GetChange("kubernetes_version")
+				return checkKubernetesUpgradePath(o.(string), n.(string))
+			},
+		},
+	}
+}
+
+// kubernetesVersionPattern e.g. `v1.24.15-vke.28` or `v1.24`
+var kubernetesVersionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// parseKubernetesVersion the patch is -1 when the version is specified to minor version
+func parseKubernetesVersion(version string) (major, minor, patch int, err error) {
+	m := kubernetesVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return 0, 0, 0, fmt.Errorf("invalid kubernetes version %q", version)
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	patch = -1
+	if m[3] != "" {
+		patch, _ = strconv.Atoi(m[3])
+	}
+	return major, minor, patch, nil
+}
+
+// checkKubernetesUpgradePath the control plane is upgraded in place one minor version at a time, downgrade is not supported
+func checkKubernetesUpgradePath(from, to string) error {
+	if from == "" || to == "" || strings.Contains(from, to) {
+		return nil
+	}
+	fromMajor, fromMinor, fromPatch, err := parseKubernetesVersion(from)
+	if err != nil {
+		return err
+	}
+	toMajor, toMinor, toPatch, err := parseKubernetesVersion(to)
+	if err != nil {
+		return err
+	}
+	if toMajor < fromMajor || (toMajor == fromMajor && toMinor < fromMinor) ||
+		(toMajor == fromMajor && toMinor == fromMinor && toPatch >= 0 && toPatch < fromPatch) {
+		return fmt.Errorf("upgrade kubernetes_version from %s to %s is not supported, downgrade is not supported", from, to)
+	}
+	if toMajor != fromMajor || toMinor > fromMinor+1 {
+		return fmt.Errorf("upgrade kubernetes_version from %s to %s is not supported, "+
+			"the cluster can only be upgraded to the next minor version v%d.%d", from, to, fromMajor, fromMinor+1)
+	}
+	return nil
+}
+
+// upgradeCallback upgrade the control plane in place and wait for it, then roll the nodes of the node pools
+// when upgrade_node_pools is true
+func (s *VestackVkeClusterService) upgradeCallback(resourceData *schema.ResourceData) bp.Callback {
+	o, n := resourceData.GetChange("kubernetes_version")
+	from, to := o.(string), n.(string)
+	return bp.Callback{
+		Call: bp.SdkCall{
+			Action:      "UpgradeCluster",
+			ContentType: bp.ContentTypeJson,
+			ConvertMode: bp.RequestConvertIgnore,
+			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
+				if err := checkKubernetesUpgradePath(from, to); err != nil {
+					return false, err
+				}
+				(*call.SdkParam)["Id"] = d.Id()
+				(*call.SdkParam)["KubernetesVersion"] = to
+				return true, nil
+			},
+			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
+				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
+				logger.Info("upgrade vke cluster %s from %s to %s", d.Id(), from, to)
+				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
+			},
+			CallError: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall, baseErr error) error {
+				return fmt.Errorf("upgrade vke cluster %s from %s to %s error: %w", d.Id(), from, to, baseErr)
+			},
+			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
+				timeout := d.Timeout(schema.TimeoutUpdate)
+				if err := s.waitClusterUpgraded(d, to, timeout); err != nil {
+					return err
+				}
+				if !d.Get("upgrade_node_pools").(bool) {
+					return nil
+				}
+				return s.upgradeNodePools(d, to, d.Get("node_upgrade_max_unavailable").(int), timeout)
+			},
+		},
+	}
+}
+
+// resumeUpgradeCallback upgrade the nodes left by an interrupted upgrade to the current kubernetes_version
+func (s *VestackVkeClusterService) resumeUpgradeCallback(resourceData *schema.ResourceData) bp.Callback {
+	return bp.Callback{
+		Call: bp.SdkCall{
+			Action:      "UpgradeNodePool",
+			ContentType: bp.ContentTypeJson,
+			ConvertMode: bp.RequestConvertIgnore,
+			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
+				return nil, s.upgradeNodePools(d, d.Get("kubernetes_version").(string), d.Get("node_upgrade_max_unavailable").(int),
+					d.Timeout(schema.TimeoutUpdate))
+			},
+		},
+	}
+}
+
+// waitClusterUpgraded the cluster may still be Running with the old version right after UpgradeCluster
+func (s *VestackVkeClusterService) waitClusterUpgraded(resourceData *schema.ResourceData, version string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{},
+		Delay:      1 * time.Second,
+		MinTimeout: 1 * time.Second,
+		Target:     []string{"Running"},
+		Timeout:    timeout,
+		Refresh: func() (result interface{}, state string, err error) {
+			cluster, err := s.ReadResource(resourceData, resourceData.Id())
+			if err != nil {
+				return nil, "", err
+			}
+			phase, err := bp.ObtainSdkValue("Status.Phase", cluster)
+			if err != nil {
+				return nil, "", err
+			}
+			current, _ := cluster["KubernetesVersion"].(string)
+			logger.Info("vke cluster %s is upgrading to %s, status %v, current version %s", resourceData.Id(), version, phase, current)
+			if phase == "Failed" {
+				return nil, "", fmt.Errorf("upgrade vke cluster %s to %s failed, current version %s", resourceData.Id(), version, current)
+			}
+			if phase == "Running" && !isKubernetesVersion(current, version) {
+				return cluster, "Upgrading", nil
+			}
+			return cluster, phase.(string), nil
+		},
+	}
+	_, err := bp.WaitForStateContext(s.Client.StopContext(), stateConf)
+	return err
+}
+
+// upgradeNodePools upgrade the nodes of every node pool of the cluster which are not at version in batches of
+// maxUnavailable nodes, the next batch is upgraded after all nodes of the batch are Running at version.
+// The ids of the nodes not upgraded yet are kept in upgrade_pending_node_ids, so an interrupted upgrade
+// is resumed in the next apply
+func (s *VestackVkeClusterService) upgradeNodePools(d *schema.ResourceData, version string, maxUnavailable int, timeout time.Duration) error {
+	clusterId := d.Id()
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+	nodePools, err := s.listClusterItems("ListNodePools", map[string]interface{}{
+		"ClusterIds": []string{clusterId},
+	})
+	if err != nil {
+		return fmt.Errorf("list node pools of vke cluster %s error: %w", clusterId, err)
+	}
+	var (
+		nodePoolIds []string
+		nodeIds     = make(map[string][]string)
+		pending     []string
+	)
+	for _, nodePool := range nodePools {
+		nodePoolId, _ := nodePool.(map[string]interface{})["Id"].(string)
+		nodes, err := s.listClusterItems("ListNodes", map[string]interface{}{
+			"ClusterIds":  []string{clusterId},
+			"NodePoolIds": []string{nodePoolId},
+		})
+		if err != nil {
+			return fmt.Errorf("list nodes of node pool %s error: %w", nodePoolId, err)
+		}
+		nodePoolIds = append(nodePoolIds, nodePoolId)
+		for _, node := range nodes {
+			id, ok := node.(map[string]interface{})["Id"].(string)
+			current, _ := node.(map[string]interface{})["KubernetesVersion"].(string)
+			if !ok || isKubernetesVersion(current, version) {
+				continue
+			}
+			nodeIds[nodePoolId] = append(nodeIds[nodePoolId], id)
+			pending = append(pending, id)
+		}
+	}
+	if err = setUpgradePendingNodeIds(d, pending); err != nil {
+		return err
+	}
+
+	total := len(pending)
+	for i, nodePoolId := range nodePoolIds {
+		ids := nodeIds[nodePoolId]
+		for start := 0; start < len(ids); start += maxUnavailable {
+			end := start + maxUnavailable
+			if end > len(ids) {
+				end = len(ids)
+			}
+			logger.Info("upgrade node pool %s (%d/%d) of vke cluster %s to %s, nodes %v, %d/%d upgraded",
+				nodePoolId, i+1, len(nodePoolIds), clusterId, version, ids[start:end], total-len(pending), total)
+			action := "UpgradeNodePool"
+			req := map[string]interface{}{
+				"ClusterId":         clusterId,
+				"Id":                nodePoolId,
+				"NodeIds":           ids[start:end],
+				"KubernetesVersion": version,
+			}
+			logger.Debug(logger.ReqFormat, action, req)
+			if _, err = s.Client.UniversalClient.DoCall(getUniversalInfo(action), &req); err == nil {
+				err = s.waitNodesUpgraded(clusterId, ids[start:end], version, timeout)
+			}
+			if err != nil {
+				return fmt.Errorf("upgrade node pool %s of vke cluster %s to %s error, %d/%d nodes upgraded, "+
+					"the remaining nodes are upgraded in the next apply: %w", nodePoolId, clusterId, version, total-len(pending), total, err)
+			}
+			pending = pending[end-start:]
+			if err = setUpgradePendingNodeIds(d, pending); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// waitNodesUpgraded wait for the nodes Running at version, the nodes may still be Running with the old version
+// right after UpgradeNodePool
+func (s *VestackVkeClusterService) waitNodesUpgraded(clusterId string, nodeIds []string, version string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{},
+		Delay:      1 * time.Second,
+		MinTimeout: 1 * time.Second,
+		Target:     []string{"Running"},
+		Timeout:    timeout,
+		Refresh: func() (result interface{}, state string, err error) {
+			nodes, err := s.listClusterItems("ListNodes", map[string]interface{}{
+				"ClusterIds": []string{clusterId},
+				"Ids":        nodeIds,
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			state = "Running"
+			for _, node := range nodes {
+				phase, _ := bp.ObtainSdkValue("Status.Phase", node)
+				current, _ := node.(map[string]interface{})["KubernetesVersion"].(string)
+				if phase == "Failed" {
+					return nil, "", fmt.Errorf("node %v status error, status: Failed", node.(map[string]interface{})["Id"])
+				}
+				if phase != "Running" || !isKubernetesVersion(current, version) {
+					state = "Updating"
+				}
+			}
+			return nodes, state, nil
+		},
+	}
+	_, err := bp.WaitForStateContext(s.Client.StopContext(), stateConf)
+	return err
+}
+
+// isKubernetesVersion the version may be specified without patch version, e.g. `v1.25`
+func isKubernetesVersion(current, version string) bool {
+	return current != "" && strings.Contains(current, version)
+}
+
+func setUpgradePendingNodeIds(d *schema.ResourceData, ids []string) error {
+	var items []interface{}
+	for _, id := range ids {
+		items = append(items, id)
+	}
+	return d.Set("upgrade_pending_node_ids", schema.NewSet(schema.HashString, items))
+}
+
+func (s *VestackVkeClusterService) listClusterItems(action string, filter map[string]interface{}) ([]interface{}, error) {
+	condition := map[string]interface{}{
+		"Filter": filter,
+	}
+	return bp.WithPageNumberQuery(condition, "PageSize", "PageNumber", 100, 1, func(m map[string]interface{}) ([]interface{}, error) {
+		logger.Debug(logger.ReqFormat, action, m)
+		resp, err := s.Client.UniversalClient.DoCall(getUniversalInfo(action), &m)
+		if err != nil {
+			return nil, err
+		}
+		results, err := bp.ObtainSdkValue("Result.Items", *resp)
+		if err != nil {
+			return nil, err
+		}
+		if results == nil {
+			return []interface{}{}, nil
+		}
+		items, ok := results.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Result.Items of %s is not Slice", action)
+		}
+		return items, nil
+	})
+}
@@ -0,0 +1,237 @@
+package cluster
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func Test_CheckKubernetesUpgradePath(t *testing.T) {
+	cases := []struct {
+		from, to string
+		err      string
+	}{
+		{from: "v1.24.15-vke.28", to: "v1.24"},
+		{from: "v1.24.15-vke.28", to: "v1.24.17-vke.1"},
+		{from: "v1.24.15-vke.28", to: "v1.26", err: "only be upgraded to the next minor version v1.25"},
+		{from: "v1.24.15-vke.28", to: "v1.25.12-vke.4"},
+		{from: "v1.24.15-vke.28", to: "v1.25"},
+		{from: "v1.24.15-vke.28", to: "v1.23.14-vke.2", err: "downgrade is not supported"},
+		{from: "v1.24.15-vke.28", to: "v1.24.10-vke.2", err: "downgrade is not supported"},
+		{from: "v1.24.15-vke.28", to: "latest", err: `invalid kubernetes version "latest"`},
+		{from: "", to: "v1.28"},
+	}
+	for _, c := range cases {
+		err := checkKubernetesUpgradePath(c.from, c.to)
+		if c.err == "" {
+			assert.NoError(t, err, c.to)
+		} else if assert.Error(t, err, c.to) {
+			assert.Contains(t, err.Error(), c.err, c.to)
+		}
+	}
+}
+
+func mockCluster(version string) map[string]interface{} {
+	return map[string]interface{}{
+		"Id":                "cc-mock",
+		"KubernetesVersion": version,
+		"Status":            map[string]interface{}{"Phase": "Running"},
+		"ClusterConfig":     map[string]interface{}{"ApiServerPublicAccessEnabled": false},
+	}
+}
+
+// mockNodes the nodes are still Running with the old version at the first ListNodes after UpgradeNodePool,
+// then Updating, then Running with the new version
+type mockNodes struct {
+	t         *testing.T
+	lock      sync.Mutex
+	ids       []string
+	versions  map[string]string
+	upgrading map[string]int
+	fail      map[int]bool
+	upgrades  int
+}
+
+const mockNewVersion = "v1.25.12-vke.4"
+
+func newMockNodes(t *testing.T, versions map[string]string) *mockNodes {
+	m := &mockNodes{
+		t:         t,
+		versions:  versions,
+		upgrading: make(map[string]int),
+		fail:      make(map[int]bool),
+	}
+	for id := range versions {
+		m.ids = append(m.ids, id)
+	}
+	sort.Strings(m.ids)
+	return m
+}
+
+func (m *mockNodes) upgradeNodePool(req *bp.MockRequest) *bp.MockResponse {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.upgrades++
+	if m.fail[m.upgrades] {
+		return &bp.MockResponse{StatusCode: 400, Error: &bp.MockError{Code: "InternalError", Message: "mock"}}
+	}
+	// the next batch is upgraded after the previous batch is Running with the new version
+	assert.Empty(m.t, m.upgrading, "batch %d", m.upgrades)
+	for _, id := range req.Params["NodeIds"].([]interface{}) {
+		m.upgrading[id.(string)] = 0
+	}
+	return &bp.MockResponse{Result: map[string]interface{}{}}
+}
+
+func (m *mockNodes) listNodes(req *bp.MockRequest) *bp.MockResponse {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ids := m.ids
+	if filter, ok := req.Params["Filter"].(map[string]interface{})["Ids"].([]interface{}); ok {
+		ids = nil
+		for _, id := range filter {
+			ids = append(ids, id.(string))
+		}
+	}
+	var items []interface{}
+	for _, id := range ids {
+		phase := "Running"
+		if polls, ok := m.upgrading[id]; ok {
+			switch polls {
+			case 0:
+			case 1:
+				phase = "Updating"
+			default:
+				m.versions[id] = mockNewVersion
+				delete(m.upgrading, id)
+			}
+			if _, ok = m.upgrading[id]; ok {
+				m.upgrading[id] = polls + 1
+			}
+		}
+		items = append(items, map[string]interface{}{
+			"Id":                id,
+			"KubernetesVersion": m.versions[id],
+			"Status":            map[string]interface{}{"Phase": phase},
+		})
+	}
+	return &bp.MockResponse{Result: map[string]interface{}{"Items": items}}
+}
+
+func Test_UpgradeCallback(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := newMockNodes(t, map[string]string{
+		"n-1": "v1.24.15-vke.28",
+		"n-2": "v1.24.15-vke.28",
+		"n-3": "v1.24.15-vke.28",
+		// already upgraded
+		"n-4": mockNewVersion,
+	})
+	nodes.fail[2] = true
+	server.On("UpgradeCluster", bp.MockResponse{Result: map[string]interface{}{}})
+	server.On("ListClusters",
+		bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{mockCluster("v1.24.15-vke.28")}}},
+		bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{mockCluster("v1.25.12-vke.4")}}},
+	)
+	server.On("ListKubeconfigs", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{}}})
+	server.On("ListNodePools", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{"Id": "np-1"},
+	}}})
+	server.OnFunc("ListNodes", nodes.listNodes)
+	server.OnFunc("UpgradeNodePool", nodes.upgradeNodePool)
+
+	svc := NewVkeClusterService(client)
+	resource := ResourceVestackVkeCluster()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"kubernetes_version":           "v1.25",
+		"upgrade_node_pools":           true,
+		"node_upgrade_max_unavailable": 2,
+	})
+	d.SetId("cc-mock")
+
+	// the second batch fails, its nodes are pending
+	callback := svc.upgradeCallback(d)
+	assert.Nil(t, callback.Call.InitWriteCall(d, resource, true))
+	err = bp.CallProcess([]bp.SdkCall{callback.Call}, d, client, svc)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "2/3 nodes upgraded")
+	}
+
+	upgrades := server.Requests("UpgradeCluster")
+	if assert.Equal(t, 1, len(upgrades)) {
+		assert.Equal(t, "cc-mock", upgrades[0].Params["Id"])
+		assert.Equal(t, "v1.25", upgrades[0].Params["KubernetesVersion"])
+	}
+	assert.True(t, len(server.Requests("ListClusters")) >= 2)
+
+	nodeUpgrades := server.Requests("UpgradeNodePool")
+	if assert.Equal(t, 2, len(nodeUpgrades)) {
+		assert.Equal(t, "np-1", nodeUpgrades[0].Params["Id"])
+		assert.Equal(t, []interface{}{"n-1", "n-2"}, nodeUpgrades[0].Params["NodeIds"])
+		assert.Equal(t, []interface{}{"n-3"}, nodeUpgrades[1].Params["NodeIds"])
+	}
+	assert.Equal(t, []interface{}{"n-3"}, d.Get("upgrade_pending_node_ids").(*schema.Set).List())
+
+	// the pending nodes plan an update which upgrades them only
+	state := d.State()
+	state.Attributes["kubernetes_version"] = "v1.25.12-vke.4"
+	diff, err := resource.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"kubernetes_version":           "v1.25",
+		"upgrade_node_pools":           true,
+		"node_upgrade_max_unavailable": 2,
+	}), client)
+	if assert.Nil(t, err) && assert.NotNil(t, diff) {
+		assert.True(t, diff.Attributes["upgrade_pending_node_ids.#"].NewComputed)
+	}
+	d, err = schema.InternalMap(resource.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callbacks := svc.ModifyResource(d, resource)
+	last := callbacks[len(callbacks)-1]
+	assert.Equal(t, "UpgradeNodePool", last.Call.Action)
+	assert.Nil(t, last.Call.InitWriteCall(d, resource, true))
+	assert.Nil(t, bp.CallProcess([]bp.SdkCall{last.Call}, d, client, svc))
+	nodeUpgrades = server.Requests("UpgradeNodePool")
+	if assert.Equal(t, 3, len(nodeUpgrades)) {
+		assert.Equal(t, []interface{}{"n-3"}, nodeUpgrades[2].Params["NodeIds"])
+		assert.Equal(t, "v1.25.12-vke.4", nodeUpgrades[2].Params["KubernetesVersion"])
+	}
+	assert.Equal(t, 0, d.Get("upgrade_pending_node_ids").(*schema.Set).Len())
+}
+
+func Test_UpgradeCallback_Error(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.On("UpgradeCluster", bp.MockResponse{StatusCode: 400, Error: &bp.MockError{Code: "InvalidParameter", Message: "unsupported version"}})
+
+	svc := NewVkeClusterService(client)
+	resource := ResourceVestackVkeCluster()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"kubernetes_version": "v1.25",
+	})
+	d.SetId("cc-mock")
+
+	callback := svc.upgradeCallback(d)
+	assert.Nil(t, callback.Call.InitWriteCall(d, resource, true))
+	err = bp.CallProcess([]bp.SdkCall{callback.Call}, d, client, svc)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "upgrade vke cluster cc-mock from  to v1.25 error")
+		assert.Contains(t, err.Error(), "unsupported version")
+	}
+	assert.Equal(t, 0, len(server.Requests("ListClusters")))
+}
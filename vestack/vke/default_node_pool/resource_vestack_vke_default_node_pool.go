@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke"
 )
 
 /*
@@ -243,6 +244,13 @@ func ResourceVestackDefaultNodePool() *schema.Resource {
 				Computed:    true,
 				Description: "Is import of the DefaultNodePool. It only works when imported, set to true.",
 			},
+			"drain": vke.DrainSchema(),
+			"retain_instance": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to retain the ECS instances of the nodes removed from `instances`, the instances are deleted with the nodes when it is `false`. Default is `true`.",
+			},
 		},
 	}
 }
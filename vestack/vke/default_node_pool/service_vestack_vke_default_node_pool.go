@@ -235,6 +235,12 @@ func (s *VestackDefaultNodePoolService) CreateResource(resourceData *schema.Reso
 				"instances": {
 					Ignore: true,
 				},
+				"drain": {
+					Ignore: true,
+				},
+				"retain_instance": {
+					Ignore: true,
+				},
 				"kubernetes_config": {
 					ConvertType: bp.ConvertJsonObject,
 					NextLevelConvert: map[string]bp.RequestConvert{
@@ -417,9 +423,6 @@ func (s *VestackDefaultNodePoolService) ModifyResource(resourceData *schema.Reso
 		},
 	})
 
-	// 更新Tags
-	calls = s.setResourceTags(resourceData, "NodePool", calls)
-
 	//修改实例
 	if resourceData.HasChange("instances") {
 		calls = s.ProcessNodeInstances(resourceData, calls)
@@ -521,6 +524,10 @@ func (s *VestackDefaultNodePoolService) ProcessNodeInstances(resourceData *schem
 						for index, id := range nodeIds {
 							(*call.SdkParam)[fmt.Sprintf("Ids.%d", index+1)] = id
 						}
+						vke.SetNodeDeletePolicy(d, *call.SdkParam)
+						if err := vke.DrainNodes(s.Client, d, clusterId, nodeIds); err != nil {
+							return false, err
+						}
 						return true, nil
 					},
 					ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
@@ -615,56 +622,11 @@ func (s *VestackDefaultNodePoolService) ProcessNodeInstances(resourceData *schem
 	return calls
 }
 
-func (s *VestackDefaultNodePoolService) setResourceTags(resourceData *schema.ResourceData, resourceType string, callbacks []bp.Callback) []bp.Callback {
-	addedTags, removedTags, _, _ := bp.GetSetDifference("tags", resourceData, bp.TagsHash, false)
-
-	removeCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "UntagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if removedTags != nil && len(removedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["TagKeys"] = make([]string, 0)
-					for _, tag := range removedTags.List() {
-						(*call.SdkParam)["TagKeys"] = append((*call.SdkParam)["TagKeys"].([]string), tag.(map[string]interface{})["key"].(string))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
-	}
-	callbacks = append(callbacks, removeCallback)
-
-	addCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "TagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if addedTags != nil && len(addedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["Tags"] = make([]map[string]interface{}, 0)
-					for _, tag := range addedTags.List() {
-						(*call.SdkParam)["Tags"] = append((*call.SdkParam)["Tags"].([]map[string]interface{}), tag.(map[string]interface{}))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
+func (s *VestackDefaultNodePoolService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "NodePool",
+		Flavor:         bp.TagFlavorJson,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
 	}
-	callbacks = append(callbacks, addCallback)
-
-	return callbacks
 }
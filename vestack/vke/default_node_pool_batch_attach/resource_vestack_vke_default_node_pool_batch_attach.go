@@ -24,7 +24,9 @@ func ResourceVestackDefaultNodePoolBatchAttach() *schema.Resource {
 			ForceNew:    true,
 			Description: "The default NodePool ID.",
 		},
-		"instances": default_node_pool.ResourceVestackDefaultNodePool().Schema["instances"],
+		"instances":       default_node_pool.ResourceVestackDefaultNodePool().Schema["instances"],
+		"drain":           default_node_pool.ResourceVestackDefaultNodePool().Schema["drain"],
+		"retain_instance": default_node_pool.ResourceVestackDefaultNodePool().Schema["retain_instance"],
 		"kubernetes_config": {
 			Type:     schema.TypeList,
 			MaxItems: 1,
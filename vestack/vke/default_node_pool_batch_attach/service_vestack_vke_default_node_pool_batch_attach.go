@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	bp "github.com/volcengine/terraform-provider-vestack/common"
 	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke"
 	"github.com/volcengine/terraform-provider-vestack/vestack/vke/default_node_pool"
 	"github.com/volcengine/terraform-provider-vestack/vestack/vke/node_pool"
 )
@@ -132,6 +133,10 @@ func (s *VestackVkeDefaultNodePoolBatchAttachService) RemoveResource(resourceDat
 						for index, id := range nodeIds {
 							(*call.SdkParam)[fmt.Sprintf("Ids.%d", index+1)] = id
 						}
+						vke.SetNodeDeletePolicy(d, *call.SdkParam)
+						if err := vke.DrainNodes(s.Client, d, clusterId, nodeIds); err != nil {
+							return false, err
+						}
 						return true, nil
 					},
 					ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
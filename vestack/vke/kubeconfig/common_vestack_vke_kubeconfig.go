@@ -0,0 +1,244 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Kubeconfig the structured kubeconfig of the current context, the certificates and key are PEM encoded
+type Kubeconfig struct {
+	Host                  string
+	ClusterCaCertificate  string
+	ClientCertificate     string
+	ClientKey             string
+	Token                 string
+	InsecureSkipTlsVerify bool
+}
+
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Token                 string `yaml:"token"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// DecodeKubeconfig the kubeconfig of the api is BASE64 encoded, the content not encoded is returned as it is
+func DecodeKubeconfig(content string) string {
+	if b, err := base64.StdEncoding.DecodeString(content); err == nil {
+		return string(b)
+	}
+	return content
+}
+
+// ParseKubeconfig parse the yaml content, the cluster and user of the current context are used when it is set
+func ParseKubeconfig(content string) (*Kubeconfig, error) {
+	var config kubeconfigFile
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil, fmt.Errorf("parse kubeconfig error: %w", err)
+	}
+	if len(config.Clusters) == 0 || len(config.Users) == 0 {
+		return nil, fmt.Errorf("kubeconfig has no cluster or user")
+	}
+	clusterIndex, userIndex := 0, 0
+	for _, c := range config.Contexts {
+		if c.Name != config.CurrentContext {
+			continue
+		}
+		for i, cluster := range config.Clusters {
+			if cluster.Name == c.Context.Cluster {
+				clusterIndex = i
+			}
+		}
+		for i, user := range config.Users {
+			if user.Name == c.Context.User {
+				userIndex = i
+			}
+		}
+	}
+	cluster := config.Clusters[clusterIndex].Cluster
+	user := config.Users[userIndex].User
+	if cluster.Server == "" {
+		return nil, fmt.Errorf("kubeconfig has no server")
+	}
+
+	result := &Kubeconfig{
+		Host:                  cluster.Server,
+		Token:                 user.Token,
+		InsecureSkipTlsVerify: cluster.InsecureSkipTLSVerify,
+	}
+	for _, field := range []struct {
+		name   string
+		data   string
+		target *string
+	}{
+		{"certificate-authority-data", cluster.CertificateAuthorityData, &result.ClusterCaCertificate},
+		{"client-certificate-data", user.ClientCertificateData, &result.ClientCertificate},
+		{"client-key-data", user.ClientKeyData, &result.ClientKey},
+	} {
+		if field.data == "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(field.data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s of kubeconfig error: %w", field.name, err)
+		}
+		*field.target = string(b)
+	}
+	return result, nil
+}
+
+// CertificateExpireTime the NotAfter of the client certificate
+func (k *Kubeconfig) CertificateExpireTime() (time.Time, bool) {
+	block, _ := pem.Decode([]byte(k.ClientCertificate))
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
+// expandKubeconfig add the structured attributes of the Kubeconfig of the ListKubeconfigs item,
+// the item is kept as it is when the Kubeconfig can not be parsed
+func expandKubeconfig(item map[string]interface{}) {
+	content, ok := item["Kubeconfig"].(string)
+	if !ok || content == "" {
+		return
+	}
+	config, err := ParseKubeconfig(DecodeKubeconfig(content))
+	if err != nil {
+		logger.Info("parse kubeconfig %v error: %s", item["Id"], err)
+		return
+	}
+	item["Host"] = config.Host
+	item["ClusterCaCertificate"] = config.ClusterCaCertificate
+	item["ClientCertificate"] = config.ClientCertificate
+	item["ClientKey"] = config.ClientKey
+	item["Token"] = config.Token
+	if s, _ := item["ExpireTime"].(string); s == "" {
+		if expireTime, ok := config.CertificateExpireTime(); ok {
+			item["ExpireTime"] = expireTime.UTC().Format(time.RFC3339)
+		}
+	}
+}
+
+// kubeconfigExpireTime the ExpireTime of the expanded ListKubeconfigs item
+func kubeconfigExpireTime(item map[string]interface{}) (time.Time, bool) {
+	s, _ := item["ExpireTime"].(string)
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// readyForRenewal whether the kubeconfig expires within renew_before of d
+func readyForRenewal(d *schema.ResourceData, item map[string]interface{}) bool {
+	renewBefore, err := time.ParseDuration(d.Get("renew_before").(string))
+	if err != nil || renewBefore <= 0 {
+		return false
+	}
+	expireTime, ok := kubeconfigExpireTime(item)
+	return ok && time.Until(expireTime) < renewBefore
+}
+
+// renewalCustomizeDiff replace the kubeconfig ready for renewal
+var renewalCustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || !diff.Get("ready_for_renewal").(bool) {
+		return nil
+	}
+	if err := diff.SetNew("ready_for_renewal", false); err != nil {
+		return err
+	}
+	return diff.ForceNew("ready_for_renewal")
+}
+
+// writeKubeconfigFile write the content to path with 0600 permissions, the file is replaced atomically
+func writeKubeconfigFile(path, content string) error {
+	if path == "" {
+		return nil
+	}
+	if old, err := ioutil.ReadFile(path); err == nil && string(old) == content {
+		return os.Chmod(path, 0600)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, ".kubeconfig-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = f.Chmod(0600); err == nil {
+		_, err = f.WriteString(content)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// kubeconfigFileMatched whether the file at path is the content, it is true when path is empty
+func kubeconfigFileMatched(path, content string) bool {
+	if path == "" {
+		return true
+	}
+	b, err := ioutil.ReadFile(path)
+	return err == nil && string(b) == content
+}
+
+// removeKubeconfigFile remove the file only when it is written by the kubeconfig, so the file of the renewed one is kept
+func removeKubeconfigFile(path, content string) error {
+	if path == "" {
+		return nil
+	}
+	old, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(old, []byte(content)) {
+		return nil
+	}
+	return os.Remove(path)
+}
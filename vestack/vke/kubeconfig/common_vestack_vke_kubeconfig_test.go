@@ -0,0 +1,236 @@
+package kubeconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+func newTestCertificate(t *testing.T, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mock"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func newTestKubeconfig(cert, key string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: admin@vke
+clusters:
+- name: other
+  cluster:
+    server: https://other:6443
+- name: vke
+  cluster:
+    server: https://10.0.0.1:6443
+    certificate-authority-data: %s
+contexts:
+- name: admin@vke
+  context:
+    cluster: vke
+    user: admin
+users:
+- name: admin
+  user:
+    client-certificate-data: %s
+    client-key-data: %s
+`, base64.StdEncoding.EncodeToString([]byte(cert)), base64.StdEncoding.EncodeToString([]byte(cert)),
+		base64.StdEncoding.EncodeToString([]byte(key)))
+}
+
+func Test_ParseKubeconfig(t *testing.T) {
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	cert, key := newTestCertificate(t, notAfter)
+	content := newTestKubeconfig(cert, key)
+
+	config, err := ParseKubeconfig(DecodeKubeconfig(base64.StdEncoding.EncodeToString([]byte(content))))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "https://10.0.0.1:6443", config.Host)
+		assert.Equal(t, cert, config.ClusterCaCertificate)
+		assert.Equal(t, cert, config.ClientCertificate)
+		assert.Equal(t, key, config.ClientKey)
+		expireTime, ok := config.CertificateExpireTime()
+		assert.True(t, ok)
+		assert.Equal(t, notAfter, expireTime.UTC())
+	}
+
+	item := map[string]interface{}{
+		"Id":         "kce-1",
+		"Kubeconfig": base64.StdEncoding.EncodeToString([]byte(content)),
+	}
+	expandKubeconfig(item)
+	assert.Equal(t, "https://10.0.0.1:6443", item["Host"])
+	assert.Equal(t, key, item["ClientKey"])
+	assert.Equal(t, notAfter.Format(time.RFC3339), item["ExpireTime"])
+
+	_, err = ParseKubeconfig("clusters: []")
+	assert.NotNil(t, err)
+	// the item of a kubeconfig not parsed is kept as it is
+	item = map[string]interface{}{"Kubeconfig": "invalid"}
+	expandKubeconfig(item)
+	assert.Equal(t, 1, len(item))
+}
+
+func Test_KubeconfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kube", "config")
+
+	assert.Nil(t, writeKubeconfigFile(path, "v1"))
+	info, err := os.Stat(path)
+	if assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	assert.Nil(t, writeKubeconfigFile(path, "v2"))
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "v2", string(b))
+
+	// the file of the renewed kubeconfig is kept
+	assert.Nil(t, removeKubeconfigFile(path, "v1"))
+	_, err = os.Stat(path)
+	assert.Nil(t, err)
+	assert.Nil(t, removeKubeconfigFile(path, "v2"))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, removeKubeconfigFile(path, "v2"))
+}
+
+func Test_KubeconfigRenewal(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("ListKubeconfigs", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{
+			"Id":         "kce-1",
+			"ClusterId":  "cc-1",
+			"Type":       "Public",
+			"ExpireTime": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+		},
+	}}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewVkeKubeconfigService(client)
+	r := ResourceVestackVkeKubeconfig()
+
+	for _, c := range []struct {
+		renewBefore string
+		ready       bool
+	}{
+		{"", false},
+		{"12h", false},
+		{"72h", true},
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"renew_before": c.renewBefore})
+		d.SetId("kce-1")
+		data, err := svc.ReadResource(d, "")
+		if assert.Nil(t, err) {
+			assert.Equal(t, c.ready, data["ReadyForRenewal"], c.renewBefore)
+		}
+	}
+
+	state := &terraform.InstanceState{
+		ID: "kce-1",
+		Attributes: map[string]string{
+			"id":                "kce-1",
+			"cluster_id":        "cc-1",
+			"type":              "Public",
+			"valid_duration":    "26280",
+			"renew_before":      "72h",
+			"ready_for_renewal": "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cluster_id":   "cc-1",
+		"type":         "Public",
+		"renew_before": "72h",
+	})
+	diff, err := r.Diff(state, config, nil)
+	if assert.Nil(t, err) && assert.NotNil(t, diff) {
+		assert.True(t, diff.RequiresNew())
+	}
+
+	state.Attributes["ready_for_renewal"] = "false"
+	diff, err = ResourceVestackVkeKubeconfig().Diff(state, config, nil)
+	if assert.Nil(t, err) && diff != nil {
+		assert.False(t, diff.RequiresNew())
+	}
+}
+
+func Test_KubeconfigFileRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+
+	server := bp.NewMockServer()
+	defer server.Close()
+	server.On("ListKubeconfigs", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{
+			"Id":         "kce-1",
+			"ClusterId":  "cc-1",
+			"Type":       "Public",
+			"Kubeconfig": base64.StdEncoding.EncodeToString([]byte("v1")),
+		},
+	}}})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := ResourceVestackVkeKubeconfig()
+	read := func() *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"output_path": path})
+		d.SetId("kce-1")
+		assert.Nil(t, r.Read(d, client))
+		return d
+	}
+
+	assert.Nil(t, writeKubeconfigFile(path, "v1"))
+	assert.Equal(t, path, read().Get("output_path"))
+
+	// the file changed or removed outside is planned to be written again
+	assert.Nil(t, writeKubeconfigFile(path, "changed"))
+	assert.Equal(t, "", read().Get("output_path"))
+	assert.Nil(t, os.Remove(path))
+	assert.Equal(t, "", read().Get("output_path"))
+
+	assert.True(t, kubeconfigFileMatched("", "v1"))
+}
@@ -113,6 +113,33 @@ func DataSourceVestackVkeKubeconfigs() *schema.Resource {
 							Computed:    true,
 							Description: "Kubeconfig data with public/private network access, returned in BASE64 encoding.",
 						},
+						"host": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The address of the api server of the cluster.",
+						},
+						"cluster_ca_certificate": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The PEM encoded CA certificate of the cluster.",
+						},
+						"client_certificate": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The PEM encoded client certificate.",
+						},
+						"client_key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Sensitive:   true,
+							Description: "The PEM encoded client key.",
+						},
+						"token": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Sensitive:   true,
+							Description: "The bearer token of the user, it is empty when the client certificate is used.",
+						},
 					},
 				},
 			},
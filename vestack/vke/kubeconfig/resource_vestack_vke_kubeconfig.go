@@ -21,6 +21,7 @@ func ResourceVestackVkeKubeconfig() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVestackVkeKubeconfigCreate,
 		Read:   resourceVestackVkeKubeconfigRead,
+		Update: resourceVestackVkeKubeconfigUpdate,
 		Delete: resourceVestackVkeKubeconfigDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -29,6 +30,7 @@ func ResourceVestackVkeKubeconfig() *schema.Resource {
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
+		CustomizeDiff: renewalCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"cluster_id": {
 				Type:        schema.TypeString,
@@ -49,6 +51,66 @@ func ResourceVestackVkeKubeconfig() *schema.Resource {
 				Default:     26280,
 				Description: "The ValidDuration of the Kubeconfig, the range of the ValidDuration is 1 hour to 43800 hour.",
 			},
+			"output_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "The path of the file the kubeconfig is written to with 0600 permissions. The file is written again when it is removed or changed outside. " +
+					"The file is removed when the Kubeconfig is deleted.",
+			},
+			"renew_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: bp.ValidateDuration,
+				Description:  "Renew the Kubeconfig when it expires within the duration, e.g. `720h`. The expiry is checked on refresh, the Kubeconfig ready for renewal is replaced by a new one.",
+			},
+			"ready_for_renewal": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the Kubeconfig expires within `renew_before`.",
+			},
+			"kubeconfig": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Kubeconfig data, returned in BASE64 encoding.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The address of the api server of the cluster.",
+			},
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PEM encoded CA certificate of the cluster.",
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PEM encoded client certificate.",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM encoded client key.",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The bearer token of the user, it is empty when the client certificate is used.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The create time of the Kubeconfig.",
+			},
+			"expire_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The expire time of the Kubeconfig.",
+			},
 		},
 	}
 }
@@ -59,10 +121,24 @@ func resourceVestackVkeKubeconfigCreate(d *schema.ResourceData, meta interface{}
 	if err != nil {
 		return fmt.Errorf("error on creating cluster  %q, %w", d.Id(), err)
 	}
-	return resourceVestackVkeKubeconfigRead(d, meta)
+	if err = readVkeKubeconfig(d, meta); err != nil {
+		return err
+	}
+	return writeKubeconfigFile(d.Get("output_path").(string), DecodeKubeconfig(d.Get("kubeconfig").(string)))
 }
 
 func resourceVestackVkeKubeconfigRead(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = readVkeKubeconfig(d, meta); err != nil {
+		return err
+	}
+	// the file removed or changed outside is written again in the next apply
+	if d.Id() != "" && !kubeconfigFileMatched(d.Get("output_path").(string), DecodeKubeconfig(d.Get("kubeconfig").(string))) {
+		return d.Set("output_path", "")
+	}
+	return nil
+}
+
+func readVkeKubeconfig(d *schema.ResourceData, meta interface{}) (err error) {
 	kubeconfigService := NewVkeKubeconfigService(meta.(*bp.SdkClient))
 	err = bp.DefaultDispatcher().Read(kubeconfigService, d, ResourceVestackVkeKubeconfig())
 	if err != nil {
@@ -71,11 +147,30 @@ func resourceVestackVkeKubeconfigRead(d *schema.ResourceData, meta interface{})
 	return err
 }
 
+func resourceVestackVkeKubeconfigUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	kubeconfigService := NewVkeKubeconfigService(meta.(*bp.SdkClient))
+	err = bp.DefaultDispatcher().Update(kubeconfigService, d, ResourceVestackVkeKubeconfig())
+	if err != nil {
+		return fmt.Errorf("error on updating cluster %q, %w", d.Id(), err)
+	}
+	if err = readVkeKubeconfig(d, meta); err != nil {
+		return err
+	}
+	content := DecodeKubeconfig(d.Get("kubeconfig").(string))
+	if d.HasChange("output_path") {
+		old, _ := d.GetChange("output_path")
+		if err = removeKubeconfigFile(old.(string), content); err != nil {
+			return err
+		}
+	}
+	return writeKubeconfigFile(d.Get("output_path").(string), content)
+}
+
 func resourceVestackVkeKubeconfigDelete(d *schema.ResourceData, meta interface{}) (err error) {
 	kubeconfigService := NewVkeKubeconfigService(meta.(*bp.SdkClient))
 	err = bp.DefaultDispatcher().Delete(kubeconfigService, d, ResourceVestackVkeKubeconfig())
 	if err != nil {
 		return fmt.Errorf("error on deleting cluster %q, %w", d.Id(), err)
 	}
-	return err
+	return removeKubeconfigFile(d.Get("output_path").(string), DecodeKubeconfig(d.Get("kubeconfig").(string)))
 }
@@ -61,7 +61,11 @@ func (s *VestackVkeKubeconfigService) ReadResources(condition map[string]interfa
 	if err != nil {
 		return data, err
 	}
-
+	for _, v := range data {
+		if item, ok := v.(map[string]interface{}); ok {
+			expandKubeconfig(item)
+		}
+	}
 	return data, err
 }
 
@@ -90,6 +94,7 @@ func (s *VestackVkeKubeconfigService) ReadResource(resourceData *schema.Resource
 	if len(data) == 0 {
 		return data, fmt.Errorf("Vke Kubeconfig %s not exist ", kubeconfigId)
 	}
+	data["ReadyForRenewal"] = readyForRenewal(resourceData, data)
 
 	return data, err
 }
@@ -118,6 +123,14 @@ func (s *VestackVkeKubeconfigService) CreateResource(resourceData *schema.Resour
 			Action:      "CreateKubeconfig",
 			ConvertMode: bp.RequestConvertAll,
 			ContentType: bp.ContentTypeJson,
+			Convert: map[string]bp.RequestConvert{
+				"output_path": {
+					Ignore: true,
+				},
+				"renew_before": {
+					Ignore: true,
+				},
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.RespFormat, call.Action, call.SdkParam)
 				//创建Kubeconfig
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke"
 )
 
 /*
@@ -170,6 +171,8 @@ func ResourceVestackVkeNode() *schema.Resource {
 				Computed:    true,
 				Description: "The node pool id.",
 			},
+			"drain":           vke.DrainSchema(),
+			"retain_instance": vke.RetainInstanceSchema(),
 		},
 	}
 	return resource
@@ -258,10 +258,10 @@ func (s *VestackVkeNodeService) RemoveResource(resourceData *schema.ResourceData
 				if len(nodePool) == 0 {
 					return false, fmt.Errorf("node pool not found")
 				}
-				// 无需删除实例，确保整体流程的完整性
-				//if nodePool[0].(map[string]interface{})["Name"] != "vke-default-nodepool" { // 非默认节点池，删除实例
-				//	(*call.SdkParam)["CascadingDeleteResources.1"] = "Ecs"
-				//}
+				vke.SetNodeDeletePolicy(d, *call.SdkParam)
+				if err = vke.DrainNodes(s.Client, d, d.Get("cluster_id").(string), []string{d.Id()}); err != nil {
+					return false, err
+				}
 				return true, nil
 			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
@@ -31,3 +31,25 @@ var kubernetesConfigLabelHash = func(v interface{}) int {
 	buf.WriteString(fmt.Sprintf("%v#%v", m["key"], m["value"]))
 	return hashcode.String(buf.String())
 }
+
+// rollingUpdateFields the changes of these fields replace the existing nodes when rolling_update is set
+var rollingUpdateFields = []string{
+	"node_config.0.image_id",
+	"node_config.0.instance_type_ids",
+	"node_config.0.system_volume",
+	"node_config.0.initialize_script",
+}
+
+// rollingUpdateCustomizeDiff plan an update to resume the interrupted rolling update
+var rollingUpdateCustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	if _, ok := diff.GetOk("rolling_update"); !ok {
+		return nil
+	}
+	if pending, ok := diff.Get("rolling_update_pending_node_ids").(*schema.Set); ok && pending.Len() > 0 {
+		return diff.SetNewComputed("rolling_update_pending_node_ids")
+	}
+	return nil
+}
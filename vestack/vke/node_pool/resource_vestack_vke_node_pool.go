@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke"
 )
 
 /*
@@ -21,10 +22,11 @@ $ terraform import vestack_vke_node_pool.default pcabe57vqtofgrbln3dp0
 
 func ResourceVestackNodePool() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVestackNodePoolCreate,
-		Read:   resourceVestackNodePoolRead,
-		Update: resourceVestackNodePoolUpdate,
-		Delete: resourceVestackNodePoolDelete,
+		Create:        resourceVestackNodePoolCreate,
+		Read:          resourceVestackNodePoolRead,
+		Update:        resourceVestackNodePoolUpdate,
+		Delete:        resourceVestackNodePoolDelete,
+		CustomizeDiff: rollingUpdateCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -61,7 +63,7 @@ func ResourceVestackNodePool() *schema.Resource {
 				},
 				ConflictsWith: []string{"auto_scaling"},
 				Description: "The list of existing ECS instance ids. Add existing instances with same type of security group under the same cluster VPC to the custom node pool.\n" +
-					"Note that removing instance ids from the list will only remove the nodes from cluster and not release the ECS instances. But deleting node pool will release the ECS instances in it.\n" +
+					"Note that removing instance ids from the list will only remove the nodes from cluster and not release the ECS instances unless `retain_instance` is `false`. But deleting node pool will release the ECS instances in it.\n" +
 					"It is not recommended to use this field, it is recommended to use `volcengine_vke_node` resource to add an existing instance to a custom node pool.",
 			},
 			"keep_instance_name": {
@@ -441,8 +443,66 @@ func ResourceVestackNodePool() *schema.Resource {
 				},
 				Description: "The KubernetesConfig of NodeConfig.",
 			},
+			"rolling_update": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"batch_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "The max number of existing nodes replaced in a batch. Default is 1.",
+						},
+						"max_surge": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description: "The number of extra nodes created by scaling out the node pool before the existing nodes of a batch are removed, it is limited to the nodes of the batch. Default is 1. " +
+								"It only takes effect on the node pools without `instance_ids`.",
+						},
+						"pause": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "0s",
+							ValidateFunc: bp.ValidateDuration,
+							Description:  "The pause between batches, e.g. `5m`. Default is `0s`.",
+						},
+						"remove_timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10m",
+							ValidateFunc: bp.ValidateDuration,
+							Description:  "The timeout of waiting for the existing nodes of a batch to be removed, e.g. `10m`. Default is `10m`.",
+						},
+					},
+				},
+				Description: "Replace the existing nodes in batches when `image_id`, `instance_type_ids`, `system_volume` or `initialize_script` of node_config changes. " +
+					"The existing nodes keep the old config when this field is not set. " +
+					"The nodes of the node pools with `instance_ids` are removed with their ECS instances retained and added back by CreateNodes, " +
+					"the other node pools are scaled out by `max_surge` and the removed nodes are recreated by the node pool.",
+			},
 
 			// computed fields
+			"drain": vke.DrainSchema(),
+			"retain_instance": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to retain the ECS instances of the nodes removed from `instance_ids`, the instances are deleted with the nodes when it is `false`. Default is `true`.",
+			},
+			"rolling_update_pending_node_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Set:      schema.HashString,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The ids of the existing nodes not replaced yet by an interrupted rolling update, they are replaced in the next apply.",
+			},
 			"node_statistics": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	bp "github.com/volcengine/terraform-provider-vestack/common"
 	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke"
 	"github.com/volcengine/terraform-provider-vestack/vestack/vpc/security_group"
 )
 
@@ -313,6 +314,15 @@ func (s *VestackNodePoolService) CreateResource(resourceData *schema.ResourceDat
 				"keep_instance_name": {
 					Ignore: true,
 				},
+				"rolling_update": {
+					Ignore: true,
+				},
+				"drain": {
+					Ignore: true,
+				},
+				"retain_instance": {
+					Ignore: true,
+				},
 				"node_config": {
 					ConvertType: bp.ConvertJsonObject,
 					NextLevelConvert: map[string]bp.RequestConvert{
@@ -880,8 +890,10 @@ func (s *VestackNodePoolService) ModifyResource(resourceData *schema.ResourceDat
 		callbacks = s.updateNodes(resourceData, callbacks)
 	}
 
-	// 更新Tags
-	callbacks = s.setResourceTags(resourceData, "NodePool", callbacks)
+	pending, _ := resourceData.GetChange("rolling_update_pending_node_ids")
+	if _, ok := resourceData.GetOk("rolling_update"); ok && (resourceData.HasChanges(rollingUpdateFields...) || pending.(*schema.Set).Len() > 0) {
+		callbacks = append(callbacks, s.rollingUpdateCallback(resourceData))
+	}
 
 	return callbacks
 }
@@ -1200,60 +1212,6 @@ func (s *VestackNodePoolService) ReadResourceId(id string) string {
 	return id
 }
 
-func (s *VestackNodePoolService) setResourceTags(resourceData *schema.ResourceData, resourceType string, callbacks []bp.Callback) []bp.Callback {
-	addedTags, removedTags, _, _ := bp.GetSetDifference("tags", resourceData, bp.TagsHash, false)
-
-	removeCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "UntagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if removedTags != nil && len(removedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["TagKeys"] = make([]string, 0)
-					for _, tag := range removedTags.List() {
-						(*call.SdkParam)["TagKeys"] = append((*call.SdkParam)["TagKeys"].([]string), tag.(map[string]interface{})["key"].(string))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
-	}
-	callbacks = append(callbacks, removeCallback)
-
-	addCallback := bp.Callback{
-		Call: bp.SdkCall{
-			Action:      "TagResources",
-			ConvertMode: bp.RequestConvertIgnore,
-			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				if addedTags != nil && len(addedTags.List()) > 0 {
-					(*call.SdkParam)["ResourceIds"] = []string{resourceData.Id()}
-					(*call.SdkParam)["ResourceType"] = resourceType
-					(*call.SdkParam)["Tags"] = make([]map[string]interface{}, 0)
-					for _, tag := range addedTags.List() {
-						(*call.SdkParam)["Tags"] = append((*call.SdkParam)["Tags"].([]map[string]interface{}), tag.(map[string]interface{}))
-					}
-					return true, nil
-				}
-				return false, nil
-			},
-			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
-				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
-				return s.Client.UniversalClient.DoCall(getUniversalInfo(call.Action), call.SdkParam)
-			},
-		},
-	}
-	callbacks = append(callbacks, addCallback)
-
-	return callbacks
-}
-
 func (s *VestackNodePoolService) updateNodes(resourceData *schema.ResourceData, callbacks []bp.Callback) []bp.Callback {
 	addedNodes, removedNodes, _, _ := bp.GetSetDifference("instance_ids", resourceData, schema.HashString, false)
 
@@ -1289,7 +1247,10 @@ func (s *VestackNodePoolService) updateNodes(resourceData *schema.ResourceData,
 
 					(*call.SdkParam)["NodePoolId"] = resourceData.Id()
 					(*call.SdkParam)["Ids"] = removeNodeList
-					(*call.SdkParam)["RetainResources"] = []string{"Ecs"}
+					vke.SetNodeDeletePolicy(d, *call.SdkParam)
+					if err = vke.DrainNodes(s.Client, d, d.Get("cluster_id").(string), removeNodeList); err != nil {
+						return false, err
+					}
 					return true, nil
 				}
 				return false, nil
@@ -1381,3 +1342,286 @@ func getUniversalInfo(actionName string) bp.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackNodePoolService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "NodePool",
+		Flavor:         bp.TagFlavorJson,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
+
+// rollingUpdateCallback replace the existing nodes in batches after the node config is updated.
+// The ids of the nodes not replaced yet are kept in rolling_update_pending_node_ids, so an interrupted
+// rolling update is resumed in the next apply
+func (s *VestackNodePoolService) rollingUpdateCallback(resourceData *schema.ResourceData) bp.Callback {
+	return bp.Callback{
+		Call: bp.SdkCall{
+			Action:      "RollingUpdateNodes",
+			ConvertMode: bp.RequestConvertIgnore,
+			ContentType: bp.ContentTypeJson,
+			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
+				return nil, s.rollingUpdateNodes(d)
+			},
+		},
+	}
+}
+
+func (s *VestackNodePoolService) rollingUpdateNodes(d *schema.ResourceData) error {
+	var (
+		batchSize     = d.Get("rolling_update.0.batch_size").(int)
+		maxSurge      = d.Get("rolling_update.0.max_surge").(int)
+		pause, _      = time.ParseDuration(d.Get("rolling_update.0.pause").(string))
+		removeTimeout = 10 * time.Minute
+		timeout       = d.Timeout(schema.TimeoutUpdate)
+		clusterId     = d.Get("cluster_id").(string)
+		_, reAdd      = d.GetOk("instance_ids")
+	)
+	if v, err := time.ParseDuration(d.Get("rolling_update.0.remove_timeout").(string)); err == nil {
+		removeTimeout = v
+	}
+
+	nodes, err := s.getAllNodeIds(d.Id())
+	if err != nil {
+		return err
+	}
+	instanceIds := make(map[string]string)
+	var existing []string
+	for _, node := range nodes {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("getAllNodeIds Node is not map")
+		}
+		id, _ := nodeMap["Id"].(string)
+		instanceIds[id], _ = nodeMap["InstanceId"].(string)
+		existing = append(existing, id)
+	}
+
+	// the pending nodes of the interrupted rolling update, or all existing nodes of a new rolling update
+	last, _ := d.Get("rolling_update_pending_node_ids").(*schema.Set)
+	if last == nil || last.Len() == 0 {
+		old, _ := d.GetChange("rolling_update_pending_node_ids")
+		last = old.(*schema.Set)
+	}
+	var pending []string
+	if last.Len() > 0 {
+		for _, id := range existing {
+			if last.Contains(id) {
+				pending = append(pending, id)
+			}
+		}
+	} else {
+		pending = existing
+	}
+	if err = setPendingNodeIds(d, pending); err != nil {
+		return err
+	}
+
+	total, replaced := len(pending), 0
+	for len(pending) > 0 {
+		if err = s.Client.StopContext().Err(); err != nil {
+			return err
+		}
+		end := batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[:end]
+		logger.Info("rolling update node pool %s: replacing nodes %v, %d/%d replaced", d.Id(), batch, replaced, total)
+
+		if reAdd {
+			err = s.reAddNodes(d, clusterId, batch, instanceIds, removeTimeout, timeout)
+		} else {
+			err = s.replaceNodes(d, clusterId, batch, maxSurge, removeTimeout, timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("rolling update node pool %s error, %d/%d nodes replaced, the remaining nodes are replaced in the next apply: %w",
+				d.Id(), replaced, total, err)
+		}
+
+		replaced += len(batch)
+		pending = pending[end:]
+		// the pending nodes removed outside are not replaced
+		if len(pending) > 0 {
+			if pending, err = s.existingNodeIds(d.Id(), pending); err != nil {
+				return err
+			}
+		}
+		if err = setPendingNodeIds(d, pending); err != nil {
+			return err
+		}
+		if len(pending) > 0 && pause > 0 {
+			logger.Info("rolling update node pool %s: pause %s before next batch", d.Id(), pause)
+			select {
+			case <-time.After(pause):
+			case <-s.Client.StopContext().Done():
+				return s.Client.StopContext().Err()
+			}
+		}
+	}
+	logger.Info("rolling update node pool %s: %d nodes replaced", d.Id(), replaced)
+	return nil
+}
+
+// existingNodeIds the ids of nodeIds still in the node pool
+func (s *VestackNodePoolService) existingNodeIds(nodePoolId string, nodeIds []string) ([]string, error) {
+	nodes, err := s.getAllNodeIds(nodePoolId)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool)
+	for _, node := range nodes {
+		if id, ok := node.(map[string]interface{})["Id"].(string); ok {
+			exists[id] = true
+		}
+	}
+	var result []string
+	for _, id := range nodeIds {
+		if exists[id] {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func setPendingNodeIds(d *schema.ResourceData, ids []string) error {
+	var items []interface{}
+	for _, id := range ids {
+		items = append(items, id)
+	}
+	return d.Set("rolling_update_pending_node_ids", schema.NewSet(schema.HashString, items))
+}
+
+// reAddNodes remove the nodes with their ECS instances retained and add the instances back,
+// the instances are initialized with the current node config
+func (s *VestackNodePoolService) reAddNodes(d *schema.ResourceData, clusterId string, nodeIds []string, instanceIds map[string]string,
+	removeTimeout, timeout time.Duration) error {
+	var instances []string
+	for _, id := range nodeIds {
+		if instanceIds[id] != "" {
+			instances = append(instances, instanceIds[id])
+		}
+	}
+	if err := s.deleteNodes(d, clusterId, nodeIds, []string{"Ecs"}, nil, removeTimeout); err != nil {
+		return err
+	}
+	action := "CreateNodes"
+	req := map[string]interface{}{
+		"ClusterId":        clusterId,
+		"NodePoolId":       d.Id(),
+		"InstanceIds":      instances,
+		"KeepInstanceName": d.Get("keep_instance_name"),
+		"ClientToken":      uuid.New().String(),
+	}
+	logger.Debug(logger.ReqFormat, action, req)
+	if _, err := s.Client.UniversalClient.DoCall(getUniversalInfo(action), &req); err != nil {
+		return err
+	}
+	return s.waitNodesRunning(d, len(instanceIds), timeout)
+}
+
+// replaceNodes scale out the node pool by maxSurge, remove the nodes with their ECS instances,
+// then restore the node count so that the node pool recreates the removed nodes with the current node config.
+// The surge is not more than the removed nodes, so restoring the node count never scales in the node pool
+// and only the given nodes are removed
+func (s *VestackNodePoolService) replaceNodes(d *schema.ResourceData, clusterId string, nodeIds []string, maxSurge int,
+	removeTimeout, timeout time.Duration) error {
+	nodes, err := s.getAllNodeIds(d.Id())
+	if err != nil {
+		return err
+	}
+	desired := len(nodes)
+	if maxSurge > len(nodeIds) {
+		maxSurge = len(nodeIds)
+	}
+	if maxSurge > 0 {
+		if err := s.scaleNodePool(d, clusterId, desired+maxSurge, timeout); err != nil {
+			return err
+		}
+	}
+	if err = s.deleteNodes(d, clusterId, nodeIds, nil, []string{"Ecs"}, removeTimeout); err != nil {
+		return err
+	}
+	return s.scaleNodePool(d, clusterId, desired, timeout)
+}
+
+func (s *VestackNodePoolService) scaleNodePool(d *schema.ResourceData, clusterId string, desired int, timeout time.Duration) error {
+	action := "UpdateNodePoolConfig"
+	req := map[string]interface{}{
+		"Id":        d.Id(),
+		"ClusterId": clusterId,
+		"AutoScaling": map[string]interface{}{
+			"DesiredReplicas": desired,
+		},
+	}
+	logger.Debug(logger.ReqFormat, action, req)
+	if _, err := s.Client.UniversalClient.DoCall(getUniversalInfo(action), &req); err != nil {
+		return err
+	}
+	return s.waitNodesRunning(d, desired, timeout)
+}
+
+func (s *VestackNodePoolService) deleteNodes(d *schema.ResourceData, clusterId string, nodeIds []string, retain, cascading []string,
+	timeout time.Duration) error {
+	if err := vke.DrainNodes(s.Client, d, clusterId, nodeIds); err != nil {
+		return err
+	}
+	action := "DeleteNodes"
+	req := map[string]interface{}{
+		"ClusterId":  clusterId,
+		"NodePoolId": d.Id(),
+		"Ids":        nodeIds,
+	}
+	if retain != nil {
+		req["RetainResources"] = retain
+	}
+	if cascading != nil {
+		req["CascadingDeleteResources"] = cascading
+	}
+	logger.Debug(logger.ReqFormat, action, req)
+	if _, err := s.Client.UniversalClient.DoCall(getUniversalInfo(action), &req); err != nil {
+		return err
+	}
+	return bp.RetryContext(s.Client.StopContext(), timeout, func() *resource.RetryError {
+		nodes, err := s.getAllNodeIds(d.Id())
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		for _, node := range nodes {
+			for _, id := range nodeIds {
+				if node.(map[string]interface{})["Id"] == id {
+					return resource.RetryableError(fmt.Errorf("node %s is still being removed", id))
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// waitNodesRunning wait for the node pool Running and at least count nodes of it Running
+func (s *VestackNodePoolService) waitNodesRunning(d *schema.ResourceData, count int, timeout time.Duration) error {
+	if _, err := bp.WaitForStateContext(s.Client.StopContext(), s.RefreshResourceState(d, []string{"Running"}, timeout, d.Id())); err != nil {
+		return err
+	}
+	return bp.RetryContext(s.Client.StopContext(), timeout, func() *resource.RetryError {
+		nodes, err := s.getAllNodeIds(d.Id())
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		running := 0
+		for _, node := range nodes {
+			phase, _ := bp.ObtainSdkValue("Status.Phase", node)
+			if phase == "Failed" {
+				return resource.NonRetryableError(fmt.Errorf("node %v status error, status: Failed", node.(map[string]interface{})["Id"]))
+			}
+			if phase == "Running" {
+				running++
+			}
+		}
+		if running < count {
+			return resource.RetryableError(fmt.Errorf("%d/%d nodes of node pool %s are running", running, count, d.Id()))
+		}
+		return nil
+	})
+}
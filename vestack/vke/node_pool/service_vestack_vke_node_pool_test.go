@@ -0,0 +1,213 @@
+package node_pool
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+// mockNodePool an in-memory node pool, the node pool creates or removes nodes to match the desired replicas
+type mockNodePool struct {
+	lock    sync.Mutex
+	nodes   map[string]string
+	created int
+}
+
+func (p *mockNodePool) items() []interface{} {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var ids []string
+	for id := range p.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var items []interface{}
+	for _, id := range ids {
+		items = append(items, map[string]interface{}{
+			"Id":         id,
+			"InstanceId": p.nodes[id],
+			"Status":     map[string]interface{}{"Phase": "Running"},
+		})
+	}
+	return items
+}
+
+func (p *mockNodePool) add(instanceId string) {
+	p.created++
+	id := fmt.Sprintf("new-%d", p.created)
+	if instanceId == "" {
+		instanceId = "i-" + id
+	}
+	p.nodes[id] = instanceId
+}
+
+func newMockNodePoolServer(t *testing.T, pool *mockNodePool) (*bp.MockServer, *VestackNodePoolService) {
+	server := bp.NewMockServer()
+	server.On("ListNodePools", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{
+			"Id":     "np-mock",
+			"Status": map[string]interface{}{"Phase": "Running"},
+			"NodeConfig": map[string]interface{}{
+				"Security": map[string]interface{}{"Login": map[string]interface{}{}, "SecurityGroupIds": []interface{}{}},
+			},
+		},
+	}}})
+	server.OnFunc("ListNodes", func(req *bp.MockRequest) *bp.MockResponse {
+		return &bp.MockResponse{Result: map[string]interface{}{"Items": pool.items()}}
+	})
+	server.OnFunc("DeleteNodes", func(req *bp.MockRequest) *bp.MockResponse {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		for _, id := range req.Params["Ids"].([]interface{}) {
+			delete(pool.nodes, id.(string))
+		}
+		return &bp.MockResponse{Result: map[string]interface{}{}}
+	})
+	server.OnFunc("CreateNodes", func(req *bp.MockRequest) *bp.MockResponse {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		for _, id := range req.Params["InstanceIds"].([]interface{}) {
+			pool.add(id.(string))
+		}
+		return &bp.MockResponse{Result: map[string]interface{}{}}
+	})
+	server.OnFunc("UpdateNodePoolConfig", func(req *bp.MockRequest) *bp.MockResponse {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		desired := int(req.Params["AutoScaling"].(map[string]interface{})["DesiredReplicas"].(float64))
+		for len(pool.nodes) < desired {
+			pool.add("")
+		}
+		for len(pool.nodes) > desired {
+			pool.created--
+			delete(pool.nodes, fmt.Sprintf("new-%d", pool.created+1))
+		}
+		return &bp.MockResponse{Result: map[string]interface{}{}}
+	})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, NewNodePoolService(client)
+}
+
+func newRollingUpdateData(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+	raw["cluster_id"] = "cc-mock"
+	raw["rolling_update"] = []interface{}{map[string]interface{}{
+		"batch_size": 2,
+		"max_surge":  1,
+		"pause":      "0s",
+	}}
+	d := schema.TestResourceDataRaw(t, ResourceVestackNodePool().Schema, raw)
+	d.SetId("np-mock")
+	return d
+}
+
+func Test_RollingUpdateNodes(t *testing.T) {
+	pool := &mockNodePool{nodes: map[string]string{"n-1": "i-1", "n-2": "i-2", "n-3": "i-3"}}
+	server, svc := newMockNodePoolServer(t, pool)
+	defer server.Close()
+
+	d := newRollingUpdateData(t, map[string]interface{}{})
+	assert.Nil(t, svc.rollingUpdateNodes(d))
+
+	deletes := server.Requests("DeleteNodes")
+	if assert.Equal(t, 2, len(deletes)) {
+		assert.Equal(t, []interface{}{"n-1", "n-2"}, deletes[0].Params["Ids"])
+		assert.Equal(t, []interface{}{"Ecs"}, deletes[0].Params["CascadingDeleteResources"])
+		assert.Equal(t, []interface{}{"n-3"}, deletes[1].Params["Ids"])
+	}
+	// scale out by max_surge and restore the node count for every batch
+	scales := server.Requests("UpdateNodePoolConfig")
+	if assert.Equal(t, 4, len(scales)) {
+		assert.Equal(t, float64(4), scales[0].Params["AutoScaling"].(map[string]interface{})["DesiredReplicas"])
+		assert.Equal(t, float64(3), scales[1].Params["AutoScaling"].(map[string]interface{})["DesiredReplicas"])
+	}
+	assert.Equal(t, 3, len(pool.nodes))
+	for id := range pool.nodes {
+		assert.Contains(t, id, "new-")
+	}
+	assert.Equal(t, 0, d.Get("rolling_update_pending_node_ids").(*schema.Set).Len())
+}
+
+func Test_RollingUpdateNodes_Resume(t *testing.T) {
+	pool := &mockNodePool{nodes: map[string]string{"n-1": "i-1", "n-2": "i-2", "n-3": "i-3"}}
+	server, svc := newMockNodePoolServer(t, pool)
+	defer server.Close()
+
+	d := newRollingUpdateData(t, map[string]interface{}{
+		"instance_ids": []interface{}{"i-1", "i-2", "i-3"},
+	})
+	// n-1 and n-2 are replaced by the interrupted rolling update, n-4 is removed outside
+	assert.Nil(t, setPendingNodeIds(d, []string{"n-3", "n-4"}))
+	assert.Nil(t, svc.rollingUpdateNodes(d))
+
+	deletes := server.Requests("DeleteNodes")
+	if assert.Equal(t, 1, len(deletes)) {
+		assert.Equal(t, []interface{}{"n-3"}, deletes[0].Params["Ids"])
+		assert.Equal(t, []interface{}{"Ecs"}, deletes[0].Params["RetainResources"])
+	}
+	creates := server.Requests("CreateNodes")
+	if assert.Equal(t, 1, len(creates)) {
+		assert.Equal(t, []interface{}{"i-3"}, creates[0].Params["InstanceIds"])
+	}
+	assert.Equal(t, 0, len(server.Requests("UpdateNodePoolConfig")))
+	assert.Equal(t, 0, d.Get("rolling_update_pending_node_ids").(*schema.Set).Len())
+}
+
+func Test_RollingUpdateNodes_Error(t *testing.T) {
+	pool := &mockNodePool{nodes: map[string]string{"n-1": "i-1", "n-2": "i-2", "n-3": "i-3"}}
+	server, svc := newMockNodePoolServer(t, pool)
+	defer server.Close()
+	// the first batch is removed, the second fails
+	server.On("DeleteNodes", bp.MockResponse{StatusCode: 400, Error: &bp.MockError{Code: "InvalidParameter", Message: "node in use"}})
+
+	d := newRollingUpdateData(t, map[string]interface{}{})
+	assert.Nil(t, d.Set("rolling_update", []interface{}{map[string]interface{}{"batch_size": 1, "max_surge": 0, "pause": "0s", "remove_timeout": "1m"}}))
+
+	err := svc.rollingUpdateNodes(d)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "1/3 nodes replaced, the remaining nodes are replaced in the next apply")
+		assert.Contains(t, err.Error(), "node in use")
+	}
+	pending := d.Get("rolling_update_pending_node_ids").(*schema.Set)
+	assert.Equal(t, 2, pending.Len())
+	assert.True(t, pending.Contains("n-2"))
+	assert.True(t, pending.Contains("n-3"))
+}
+
+func Test_RollingUpdateNodes_Surge(t *testing.T) {
+	pool := &mockNodePool{nodes: map[string]string{"n-1": "i-1", "n-2": "i-2", "n-3": "i-3"}}
+	server, svc := newMockNodePoolServer(t, pool)
+	defer server.Close()
+	// n-3 is removed outside during the first batch
+	server.OnFunc("DeleteNodes", func(req *bp.MockRequest) *bp.MockResponse {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		for _, id := range req.Params["Ids"].([]interface{}) {
+			delete(pool.nodes, id.(string))
+		}
+		delete(pool.nodes, "n-3")
+		return &bp.MockResponse{Result: map[string]interface{}{}}
+	})
+
+	d := newRollingUpdateData(t, map[string]interface{}{})
+	assert.Nil(t, d.Set("rolling_update", []interface{}{map[string]interface{}{"batch_size": 1, "max_surge": 3, "pause": "0s", "remove_timeout": "1m"}}))
+	assert.Nil(t, svc.rollingUpdateNodes(d))
+
+	deletes := server.Requests("DeleteNodes")
+	if assert.Equal(t, 2, len(deletes)) {
+		assert.Equal(t, []interface{}{"n-1"}, deletes[0].Params["Ids"])
+		assert.Equal(t, []interface{}{"n-2"}, deletes[1].Params["Ids"])
+	}
+	// the surge is limited to the batch size, the node count is restored by scaling out only
+	for _, scale := range server.Requests("UpdateNodePoolConfig") {
+		assert.True(t, scale.Params["AutoScaling"].(map[string]interface{})["DesiredReplicas"].(float64) <= 4)
+	}
+	assert.Equal(t, 0, d.Get("rolling_update_pending_node_ids").(*schema.Set).Len())
+}
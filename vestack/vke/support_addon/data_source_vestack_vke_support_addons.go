@@ -97,6 +97,11 @@ func DataSourceVestackVkeVkeSupportedAddons() *schema.Resource {
 										Description: "The compatible version list.",
 										Elem:        &schema.Schema{Type: schema.TypeString},
 									},
+									"config_schema": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The JSON schema of the config of the addon version, the config of `vestack_vke_addon` is validated against it.",
+									},
 									"compatibilities": {
 										Type:        schema.TypeList,
 										Computed:    true,
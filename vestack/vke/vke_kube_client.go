@@ -0,0 +1,223 @@
+package vke
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke/kubeconfig"
+)
+
+// KubeClient a minimal client of the kubernetes api, authenticated by a kubeconfig of the cluster
+type KubeClient struct {
+	server     string
+	token      string
+	httpClient *http.Client
+
+	lock sync.Mutex
+	// evictionVersion the api version of Eviction served by the cluster, detected by the first eviction
+	evictionVersion string
+}
+
+// NewKubeClient the kubeconfig is the yaml content, the current context is used when it is set
+func NewKubeClient(content string) (*KubeClient, error) {
+	config, err := kubeconfig.ParseKubeconfig(content)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipTlsVerify,
+	}
+	if config.ClusterCaCertificate != "" {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(config.ClusterCaCertificate)) {
+			return nil, fmt.Errorf("invalid certificate-authority-data")
+		}
+	}
+	if config.ClientCertificate != "" && config.ClientKey != "" {
+		pair, err := tls.X509KeyPair([]byte(config.ClientCertificate), []byte(config.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate error: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return &KubeClient{
+		server: strings.TrimRight(config.Host, "/"),
+		token:  config.Token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// KubeApiError the error status of the kubernetes api
+type KubeApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *KubeApiError) Error() string {
+	return fmt.Sprintf("kubernetes api error, status: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// IsKubeApiStatus whether err is a KubeApiError of statusCode
+func IsKubeApiStatus(err error, statusCode int) bool {
+	e, ok := err.(*KubeApiError)
+	return ok && e.StatusCode == statusCode
+}
+
+func (c *KubeClient) do(ctx context.Context, method, path, contentType string, body interface{}, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, c.server+path, reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		status := struct {
+			Message string `json:"message"`
+		}{}
+		if json.Unmarshal(b, &status) != nil || status.Message == "" {
+			status.Message = string(b)
+		}
+		return &KubeApiError{
+			StatusCode: resp.StatusCode,
+			Message:    status.Message,
+		}
+	}
+	if out != nil {
+		return json.Unmarshal(b, out)
+	}
+	return nil
+}
+
+// CordonNode mark the node unschedulable
+func (c *KubeClient) CordonNode(ctx context.Context, name string) error {
+	return c.setUnschedulable(ctx, name, true)
+}
+
+// UncordonNode mark the node schedulable
+func (c *KubeClient) UncordonNode(ctx context.Context, name string) error {
+	return c.setUnschedulable(ctx, name, false)
+}
+
+func (c *KubeClient) setUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"unschedulable": unschedulable,
+		},
+	}
+	return c.do(ctx, http.MethodPatch, "/api/v1/nodes/"+url.PathEscape(name), "application/merge-patch+json", patch, nil)
+}
+
+// KubePod the fields of pod used by drain
+type KubePod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Annotations     map[string]string `json:"annotations"`
+		OwnerReferences []struct {
+			Kind       string `json:"kind"`
+			Controller bool   `json:"controller"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// ListNodePods list the pods of all namespaces scheduled to the node
+func (c *KubeClient) ListNodePods(ctx context.Context, nodeName string) ([]KubePod, error) {
+	list := struct {
+		Items []KubePod `json:"items"`
+	}{}
+	query := url.Values{}
+	query.Set("fieldSelector", "spec.nodeName="+nodeName)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/pods?"+query.Encode(), "", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// EvictPod evict the pod by the eviction api, so that the PodDisruptionBudgets are respected.
+// A KubeApiError of 429 is returned when the eviction is blocked by a PodDisruptionBudget.
+// The clusters older than 1.22 only serve policy/v1beta1, which is used when policy/v1 is not found
+func (c *KubeClient) EvictPod(ctx context.Context, namespace, name string) error {
+	c.lock.Lock()
+	version := c.evictionVersion
+	c.lock.Unlock()
+	if version != "" {
+		return c.evictPod(ctx, version, namespace, name)
+	}
+
+	err := c.evictPod(ctx, "policy/v1", namespace, name)
+	if IsKubeApiStatus(err, http.StatusNotFound) {
+		// the pod is not found by both versions when it is gone
+		if err = c.evictPod(ctx, "policy/v1beta1", namespace, name); err == nil {
+			c.setEvictionVersion("policy/v1beta1")
+		}
+		return err
+	}
+	if err == nil {
+		c.setEvictionVersion("policy/v1")
+	}
+	return err
+}
+
+func (c *KubeClient) setEvictionVersion(version string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.evictionVersion = version
+}
+
+func (c *KubeClient) evictPod(ctx context.Context, version, namespace, name string) error {
+	eviction := map[string]interface{}{
+		"apiVersion": version,
+		"kind":       "Eviction",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/eviction", url.PathEscape(namespace), url.PathEscape(name))
+	return c.do(ctx, http.MethodPost, path, "application/json", eviction, nil)
+}
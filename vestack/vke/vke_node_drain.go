@@ -0,0 +1,297 @@
+package vke
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+	"github.com/volcengine/terraform-provider-vestack/logger"
+	"github.com/volcengine/terraform-provider-vestack/vestack/vke/kubeconfig"
+)
+
+// drainPollInterval the interval of evicting the remaining pods of a draining node
+var drainPollInterval = 5 * time.Second
+
+// uncordonTimeout the timeout of uncordoning the nodes when the drain fails
+var uncordonTimeout = 1 * time.Minute
+
+// DrainSchema the drain block of the resources deleting vke nodes
+func DrainSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		MaxItems: 1,
+		Optional: true,
+		Description: "Cordon the nodes and evict their pods before the nodes are deleted. The pods are evicted by the eviction api, " +
+			"so the PodDisruptionBudgets are respected. The DaemonSet pods and the static pods are not evicted. " +
+			"The kubeconfig of the cluster is required, the public one is preferred, create it by `vestack_vke_kubeconfig`.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "10m",
+					ValidateFunc: bp.ValidateDuration,
+					Description:  "The timeout of evicting the pods of the nodes, e.g. `10m`. Default is `10m`.",
+				},
+				"force": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Whether to delete the nodes when the pods are not evicted in timeout or the kubernetes api is not accessible. Default is `false`, the deletion fails and the nodes are uncordoned.",
+				},
+			},
+		},
+	}
+}
+
+// RetainInstanceSchema the choice of the ECS instances of the deleted vke nodes
+func RetainInstanceSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     true,
+		Description: "Whether to retain the ECS instances when the nodes are deleted, the instances are deleted with the nodes when it is `false`. Default is `true`.",
+	}
+}
+
+// SetNodeDeletePolicy set RetainResources or CascadingDeleteResources of DeleteNodes by retain_instance of d
+func SetNodeDeletePolicy(d *schema.ResourceData, param map[string]interface{}) {
+	if retain, ok := d.Get("retain_instance").(bool); ok && !retain {
+		param["CascadingDeleteResources"] = []string{"Ecs"}
+		return
+	}
+	param["RetainResources"] = []string{"Ecs"}
+}
+
+// DrainOption the drain block of a resource
+type DrainOption struct {
+	Timeout time.Duration
+	Force   bool
+}
+
+// GetDrainOption return false when the drain block of d is not set
+func GetDrainOption(d *schema.ResourceData) (DrainOption, bool) {
+	option := DrainOption{
+		Timeout: 10 * time.Minute,
+	}
+	if _, ok := d.GetOk("drain"); !ok {
+		return option, false
+	}
+	if v, err := time.ParseDuration(d.Get("drain.0.timeout").(string)); err == nil {
+		option.Timeout = v
+	}
+	option.Force = d.Get("drain.0.force").(bool)
+	return option, true
+}
+
+// DrainNodes cordon and drain the vke nodes before they are deleted, do nothing when the drain block of d is not set
+func DrainNodes(client *bp.SdkClient, d *schema.ResourceData, clusterId string, nodeIds []string) error {
+	option, ok := GetDrainOption(d)
+	if !ok || len(nodeIds) == 0 {
+		return nil
+	}
+	err := drainNodes(client, clusterId, nodeIds, option)
+	if err != nil && option.Force {
+		logger.Warn("drain vke nodes %v of cluster %s error, the nodes are deleted by force: %s", nodeIds, clusterId, err)
+		return nil
+	}
+	return err
+}
+
+// drainNodes the cordoned nodes are uncordoned when the drain fails and the nodes are not deleted by force
+func drainNodes(client *bp.SdkClient, clusterId string, nodeIds []string, option DrainOption) (err error) {
+	ctx, cancel := context.WithTimeout(client.StopContext(), option.Timeout)
+	defer cancel()
+
+	content, err := getClusterKubeconfig(client, clusterId)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := NewKubeClient(content)
+	if err != nil {
+		return fmt.Errorf("kubeconfig of cluster %s error: %w", clusterId, err)
+	}
+	names, err := getNodeNames(client, clusterId, nodeIds)
+	if err != nil {
+		return err
+	}
+
+	// cordon all nodes first, so the evicted pods are never scheduled to the other draining nodes
+	var cordoned []string
+	defer func() {
+		if err != nil && !option.Force && len(cordoned) > 0 {
+			err = uncordonNodes(client.StopContext(), kubeClient, cordoned, err)
+		}
+	}()
+	for _, name := range names {
+		err = kubeClient.CordonNode(ctx, name)
+		if IsKubeApiStatus(err, http.StatusNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cordon vke node %s error: %w", name, err)
+		}
+		cordoned = append(cordoned, name)
+	}
+	for _, name := range cordoned {
+		if err = drainNode(ctx, kubeClient, name); err != nil {
+			return fmt.Errorf("drain vke node %s error: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// uncordonNodes the nodes which fail to be uncordoned are reported in the error of drain
+func uncordonNodes(ctx context.Context, kubeClient *KubeClient, names []string, drainErr error) error {
+	ctx, cancel := context.WithTimeout(ctx, uncordonTimeout)
+	defer cancel()
+	var (
+		failed []string
+		err    error
+	)
+	for _, name := range names {
+		if e := kubeClient.UncordonNode(ctx, name); e != nil && !IsKubeApiStatus(e, http.StatusNotFound) {
+			failed = append(failed, name)
+			err = e
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w, the nodes %s are still cordoned, uncordon them error: %s", drainErr, strings.Join(failed, ", "), err)
+	}
+	logger.Info("vke nodes %v are uncordoned since the drain fails", names)
+	return drainErr
+}
+
+// drainNode evict the pods of the node until all of them are gone, the evictions blocked by
+// PodDisruptionBudgets are retried until ctx is done
+func drainNode(ctx context.Context, kubeClient *KubeClient, name string) error {
+	var remaining []string
+	for {
+		pods, err := kubeClient.ListNodePods(ctx, name)
+		if err != nil {
+			return drainError(ctx, remaining, err)
+		}
+		remaining = nil
+		for _, pod := range pods {
+			if !evictable(pod) {
+				continue
+			}
+			podName := pod.Metadata.Namespace + "/" + pod.Metadata.Name
+			remaining = append(remaining, podName)
+			err = kubeClient.EvictPod(ctx, pod.Metadata.Namespace, pod.Metadata.Name)
+			switch {
+			case err == nil || IsKubeApiStatus(err, http.StatusNotFound):
+			case IsKubeApiStatus(err, http.StatusTooManyRequests):
+				logger.Info("evict pod %s of vke node %s is blocked by PodDisruptionBudget, retry later", podName, name)
+			default:
+				return drainError(ctx, remaining, fmt.Errorf("evict pod %s error: %w", podName, err))
+			}
+		}
+		if len(remaining) == 0 {
+			logger.Info("vke node %s is drained", name)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return drainError(ctx, remaining, ctx.Err())
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// drainError the pods not evicted are returned when the drain is timeout
+func drainError(ctx context.Context, remaining []string, err error) error {
+	if ctx.Err() == nil || len(remaining) == 0 {
+		return err
+	}
+	return fmt.Errorf("%d pods are not evicted: %s: %w", len(remaining), strings.Join(remaining, ", "), ctx.Err())
+}
+
+// evictable the finished pods, the static pods and the DaemonSet pods are not evicted
+func evictable(pod KubePod) bool {
+	if pod.Status.Phase == "Succeeded" || pod.Status.Phase == "Failed" {
+		return false
+	}
+	if _, ok := pod.Metadata.Annotations["kubernetes.io/config.mirror"]; ok {
+		return false
+	}
+	for _, owner := range pod.Metadata.OwnerReferences {
+		if owner.Controller && owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// getClusterKubeconfig the public kubeconfig is preferred, the content is decoded from BASE64
+func getClusterKubeconfig(client *bp.SdkClient, clusterId string) (string, error) {
+	kubeconfigs, err := kubeconfig.NewVkeKubeconfigService(client).ReadResources(map[string]interface{}{
+		"Filter": map[string]interface{}{
+			"ClusterIds": []string{clusterId},
+			"Types":      []string{"Public", "Private"},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	var content string
+	for _, v := range kubeconfigs {
+		c, _ := bp.ObtainSdkValue("Kubeconfig", v)
+		t, _ := bp.ObtainSdkValue("Type", v)
+		if s, ok := c.(string); ok && s != "" && (content == "" || t == "Public") {
+			content = s
+		}
+	}
+	if content == "" {
+		return "", fmt.Errorf("no kubeconfig of vke cluster %s to drain the nodes, create one by vestack_vke_kubeconfig", clusterId)
+	}
+	return kubeconfig.DecodeKubeconfig(content), nil
+}
+
+// getNodeNames the kubernetes node names of the vke nodes, the nodes not exist are skipped
+func getNodeNames(client *bp.SdkClient, clusterId string, nodeIds []string) ([]string, error) {
+	action := "ListNodes"
+	req := map[string]interface{}{
+		"Filter": map[string]interface{}{
+			"ClusterIds": []string{clusterId},
+			"Ids":        nodeIds,
+		},
+	}
+	nodes, err := bp.WithPageNumberQuery(req, "PageSize", "PageNumber", 100, 1, func(condition map[string]interface{}) ([]interface{}, error) {
+		logger.Debug(logger.ReqFormat, action, condition)
+		resp, err := client.UniversalClient.DoCall(getUniversalInfo(action), &condition)
+		if err != nil {
+			return nil, err
+		}
+		results, err := bp.ObtainSdkValue("Result.Items", *resp)
+		if err != nil {
+			return nil, err
+		}
+		items, _ := results.([]interface{})
+		return items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, node := range nodes {
+		if name, _ := bp.ObtainSdkValue("Name", node); name != nil && name != "" {
+			names = append(names, name.(string))
+		}
+	}
+	return names, nil
+}
+
+func getUniversalInfo(actionName string) bp.UniversalInfo {
+	return bp.UniversalInfo{
+		ServiceName: "vke",
+		Version:     "2022-05-12",
+		HttpMethod:  bp.POST,
+		ContentType: bp.ApplicationJSON,
+		Action:      actionName,
+	}
+}
@@ -0,0 +1,293 @@
+package vke
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+	bp "github.com/volcengine/terraform-provider-vestack/common"
+)
+
+// mockKubeApi an in-memory kubernetes api of the pods of nodes, the evictions of the pods in blocked
+// are rejected by 429 until blocked reaches 0, the uncordons are rejected when uncordonError is set.
+// The Eviction of policy/v1 is not found when legacyEviction is set, as the clusters older than 1.22
+type mockKubeApi struct {
+	lock             sync.Mutex
+	pods             map[string][]KubePod
+	blocked          map[string]int
+	cordoned         []string
+	uncordoned       []string
+	uncordonError    bool
+	evictions        []string
+	legacyEviction   bool
+	evictionVersions []string
+}
+
+func newMockPod(namespace, name, ownerKind string) KubePod {
+	pod := KubePod{}
+	pod.Metadata.Namespace = namespace
+	pod.Metadata.Name = name
+	pod.Status.Phase = "Running"
+	if ownerKind != "" {
+		pod.Metadata.OwnerReferences = append(pod.Metadata.OwnerReferences, struct {
+			Kind       string `json:"kind"`
+			Controller bool   `json:"controller"`
+		}{Kind: ownerKind, Controller: true})
+	}
+	return pod
+}
+
+func (k *mockKubeApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if r.Header.Get("Authorization") != "Bearer mock-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/api/v1/nodes/"):
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/")
+		if _, ok := k.pods[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		switch string(body) {
+		case `{"spec":{"unschedulable":true}}`:
+			k.cordoned = append(k.cordoned, name)
+		case `{"spec":{"unschedulable":false}}`:
+			if k.uncordonError {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			k.uncordoned = append(k.uncordoned, name)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/pods":
+		node := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "spec.nodeName=")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": k.pods[node]})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/eviction"):
+		var eviction struct {
+			ApiVersion string `json:"apiVersion"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&eviction)
+		k.evictionVersions = append(k.evictionVersions, eviction.ApiVersion)
+		if k.legacyEviction && eviction.ApiVersion != "policy/v1beta1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		parts := strings.Split(r.URL.Path, "/")
+		podName := parts[4] + "/" + parts[6]
+		k.evictions = append(k.evictions, podName)
+		if k.blocked[podName] > 0 {
+			k.blocked[podName]--
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"Cannot evict pod as it would violate the pod's disruption budget."}`))
+			return
+		}
+		for node, pods := range k.pods {
+			var remaining []KubePod
+			for _, pod := range pods {
+				if pod.Metadata.Namespace+"/"+pod.Metadata.Name != podName {
+					remaining = append(remaining, pod)
+				}
+			}
+			k.pods[node] = remaining
+		}
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newMockDrainServer(t *testing.T, kube *mockKubeApi) (*bp.MockServer, *httptest.Server, *bp.SdkClient) {
+	kubeServer := httptest.NewServer(kube)
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: mock
+clusters:
+- name: mock
+  cluster:
+    server: %s
+contexts:
+- name: mock
+  context:
+    cluster: mock
+    user: mock
+users:
+- name: mock
+  user:
+    token: mock-token
+`, kubeServer.URL)
+
+	server := bp.NewMockServer()
+	server.On("ListKubeconfigs", bp.MockResponse{Result: map[string]interface{}{"Items": []interface{}{
+		map[string]interface{}{
+			"Id":         "kce-mock",
+			"Type":       "Private",
+			"Kubeconfig": base64.StdEncoding.EncodeToString([]byte(kubeconfig)),
+		},
+	}}})
+	server.OnFunc("ListNodes", func(req *bp.MockRequest) *bp.MockResponse {
+		var items []interface{}
+		ids, _ := bp.ObtainSdkValue("Filter.Ids", req.Params)
+		for _, id := range ids.([]interface{}) {
+			items = append(items, map[string]interface{}{
+				"Id":   id,
+				"Name": "node-" + id.(string),
+			})
+		}
+		return &bp.MockResponse{Result: map[string]interface{}{"Items": items}}
+	})
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, kubeServer, client
+}
+
+func newDrainData(t *testing.T, drain map[string]interface{}) *schema.ResourceData {
+	raw := map[string]interface{}{}
+	if drain != nil {
+		raw["drain"] = []interface{}{drain}
+	}
+	return schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"drain":           DrainSchema(),
+		"retain_instance": RetainInstanceSchema(),
+	}, raw)
+}
+
+func Test_DrainNodes(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = 10 * time.Millisecond
+
+	kube := &mockKubeApi{
+		pods: map[string][]KubePod{
+			"node-n-1": {
+				newMockPod("default", "web-1", "ReplicaSet"),
+				newMockPod("kube-system", "fluentd-1", "DaemonSet"),
+			},
+			"node-n-2": {
+				newMockPod("default", "web-2", "ReplicaSet"),
+			},
+		},
+		blocked: map[string]int{"default/web-2": 2},
+	}
+	server, kubeServer, client := newMockDrainServer(t, kube)
+	defer server.Close()
+	defer kubeServer.Close()
+
+	d := newDrainData(t, map[string]interface{}{"timeout": "10s"})
+	assert.Nil(t, DrainNodes(client, d, "cc-mock", []string{"n-1", "n-2", "n-gone"}))
+
+	// all nodes are cordoned before any pod is evicted
+	assert.Equal(t, []string{"node-n-1", "node-n-2"}, kube.cordoned)
+	// the DaemonSet pods are not evicted, the evictions blocked by PodDisruptionBudget are retried
+	assert.Equal(t, []string{"default/web-1", "default/web-2", "default/web-2", "default/web-2"}, kube.evictions)
+	assert.Equal(t, 1, len(kube.pods["node-n-1"]))
+	assert.Equal(t, 0, len(kube.pods["node-n-2"]))
+}
+
+func Test_DrainNodes_LegacyEviction(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = 10 * time.Millisecond
+
+	kube := &mockKubeApi{
+		pods: map[string][]KubePod{
+			"node-n-1": {
+				newMockPod("default", "web-1", "ReplicaSet"),
+				newMockPod("default", "web-2", "ReplicaSet"),
+			},
+		},
+		legacyEviction: true,
+	}
+	server, kubeServer, client := newMockDrainServer(t, kube)
+	defer server.Close()
+	defer kubeServer.Close()
+
+	assert.Nil(t, DrainNodes(client, newDrainData(t, map[string]interface{}{"timeout": "10s"}), "cc-mock", []string{"n-1"}))
+	assert.Equal(t, []string{"default/web-1", "default/web-2"}, kube.evictions)
+	assert.Equal(t, 0, len(kube.pods["node-n-1"]))
+	// policy/v1beta1 is used directly once detected
+	assert.Equal(t, []string{"policy/v1", "policy/v1beta1", "policy/v1beta1"}, kube.evictionVersions)
+}
+
+func Test_DrainNodes_Timeout(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = 10 * time.Millisecond
+
+	newKube := func() *mockKubeApi {
+		return &mockKubeApi{
+			pods: map[string][]KubePod{
+				"node-n-1": {newMockPod("default", "web-1", "ReplicaSet")},
+			},
+			blocked: map[string]int{"default/web-1": 1000},
+		}
+	}
+
+	// the nodes are not deleted, they are uncordoned
+	kube := newKube()
+	server, kubeServer, client := newMockDrainServer(t, kube)
+	defer server.Close()
+	defer kubeServer.Close()
+	err := DrainNodes(client, newDrainData(t, map[string]interface{}{"timeout": "100ms"}), "cc-mock", []string{"n-1"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "drain vke node node-n-1 error: 1 pods are not evicted: default/web-1")
+	}
+	assert.Equal(t, []string{"node-n-1"}, kube.uncordoned)
+
+	failedKube := newKube()
+	failedKube.uncordonError = true
+	failedServer, failedKubeServer, failedClient := newMockDrainServer(t, failedKube)
+	defer failedServer.Close()
+	defer failedKubeServer.Close()
+	err = DrainNodes(failedClient, newDrainData(t, map[string]interface{}{"timeout": "100ms"}), "cc-mock", []string{"n-1"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "1 pods are not evicted: default/web-1")
+		assert.Contains(t, err.Error(), "the nodes node-n-1 are still cordoned")
+	}
+
+	// the nodes are deleted by force, they are kept cordoned
+	forceKube := newKube()
+	forceServer, forceKubeServer, forceClient := newMockDrainServer(t, forceKube)
+	defer forceServer.Close()
+	defer forceKubeServer.Close()
+	assert.Nil(t, DrainNodes(forceClient, newDrainData(t, map[string]interface{}{"timeout": "100ms", "force": true}), "cc-mock", []string{"n-1"}))
+	assert.Empty(t, forceKube.uncordoned)
+}
+
+func Test_DrainNodes_NotSet(t *testing.T) {
+	server := bp.NewMockServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, DrainNodes(client, newDrainData(t, nil), "cc-mock", []string{"n-1"}))
+	assert.Equal(t, 0, len(server.Requests("ListKubeconfigs")))
+}
+
+func Test_SetNodeDeletePolicy(t *testing.T) {
+	param := map[string]interface{}{}
+	SetNodeDeletePolicy(newDrainData(t, nil), param)
+	assert.Equal(t, map[string]interface{}{"RetainResources": []string{"Ecs"}}, param)
+
+	param = map[string]interface{}{}
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"retain_instance": RetainInstanceSchema(),
+	}, map[string]interface{}{"retain_instance": false})
+	SetNodeDeletePolicy(d, param)
+	assert.Equal(t, map[string]interface{}{"CascadingDeleteResources": []string{"Ecs"}}, param)
+}
@@ -218,6 +218,7 @@ func DataSourceVestackNetworkAcls() *schema.Resource {
 								},
 							},
 						},
+						"tags": bp.TagsSchemaComputed(),
 					},
 				},
 			},
@@ -180,6 +180,7 @@ func ResourceVestackNetworkAcl() *schema.Resource {
 				Optional:    true,
 				Description: "The project name of the network acl.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 }
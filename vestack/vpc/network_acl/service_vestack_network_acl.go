@@ -154,6 +154,9 @@ func (s *VestackNetworkAclService) CreateResource(resourceData *schema.ResourceD
 			Action:      "CreateNetworkAcl",
 			ConvertMode: bp.RequestConvertAll,
 			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
 				"ingress_acl_entries": {
 					Ignore: true,
 				},
@@ -246,6 +249,9 @@ func (s *VestackNetworkAclService) ModifyResource(resourceData *schema.ResourceD
 			Action:      "ModifyNetworkAclAttributes",
 			ConvertMode: bp.RequestConvertAll,
 			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
 				"ingress_acl_entries": {
 					Ignore: true,
 				},
@@ -458,3 +464,12 @@ func (s *VestackNetworkAclService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackNetworkAclService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "networkacl",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -20,12 +20,10 @@ $ terraform import vestack_network_acl_associate.default nacl-172leak37mi9s4d1w3
 
 func ResourceVestackNetworkAclAssociate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVestackAclAssociateCreate,
-		Read:   resourceVestackAclAssociateRead,
-		Delete: resourceVestackAclAssociateDelete,
-		Importer: &schema.ResourceImporter{
-			State: aclAssociateImporter,
-		},
+		Create:   resourceVestackAclAssociateCreate,
+		Read:     resourceVestackAclAssociateRead,
+		Delete:   resourceVestackAclAssociateDelete,
+		Importer: aclAssociateCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
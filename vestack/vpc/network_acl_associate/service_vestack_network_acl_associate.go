@@ -3,7 +3,6 @@ package network_acl_associate
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -70,9 +69,9 @@ func (s *VestackNetworkAclAssociateService) ReadResource(resourceData *schema.Re
 		associateId = resourceData.Id()
 	}
 
-	ids := strings.Split(associateId, ":")
-	if len(ids) != 2 {
-		return map[string]interface{}{}, fmt.Errorf("invalid acl associateId: %s", associateId)
+	ids, err := aclAssociateCompositeId.Decode(associateId)
+	if err != nil {
+		return map[string]interface{}{}, err
 	}
 
 	networkAclId := ids[0]
@@ -137,7 +136,10 @@ func (s *VestackNetworkAclAssociateService) CreateResource(resourceData *schema.
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
 				// ResourceData中，network_acl_associate的Id形式为'network_acl_id:resource_id'
-				id := fmt.Sprintf("%s:%s", d.Get("network_acl_id"), d.Get("resource_id"))
+				id, err := aclAssociateCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
 				d.SetId(id)
 				return nil
 			},
@@ -166,10 +168,9 @@ func (s *VestackNetworkAclAssociateService) RemoveResource(resourceData *schema.
 			Action:      "DisassociateNetworkAcl",
 			ConvertMode: bp.RequestConvertIgnore,
 			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
-				aclAssociateId := d.Id()
-				ids := strings.Split(aclAssociateId, ":")
-				if len(ids) != 2 {
-					return false, fmt.Errorf("error network acl associate id: %s", aclAssociateId)
+				ids, err := aclAssociateCompositeId.Decode(d.Id())
+				if err != nil {
+					return false, err
 				}
 				(*call.SdkParam)["NetworkAclId"] = ids[0]
 				(*call.SdkParam)["Resource.1.ResourceId"] = ids[1]
@@ -27,38 +27,33 @@ func (s *VestackNetworkInterfaceService) GetClient() *bp.SdkClient {
 }
 
 func (s *VestackNetworkInterfaceService) ReadResources(m map[string]interface{}) (data []interface{}, err error) {
-	var (
-		resp    *map[string]interface{}
-		results interface{}
-		ok      bool
-	)
-	return bp.WithPageNumberQuery(m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, error) {
+	return bp.WithParallelPageNumberQuery(s, m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, int, error) {
 		vpcClient := s.Client.VpcClient
 		action := "DescribeNetworkInterfaces"
 		logger.Debug(logger.ReqFormat, action, condition)
-		if condition == nil {
-			resp, err = vpcClient.DescribeNetworkInterfacesCommon(nil)
-			if err != nil {
-				return data, err
-			}
-		} else {
-			resp, err = vpcClient.DescribeNetworkInterfacesCommon(&condition)
-			if err != nil {
-				return data, err
-			}
+		resp, err := vpcClient.DescribeNetworkInterfacesCommon(&condition)
+		if err != nil {
+			return nil, 0, err
 		}
-		logger.Debug(logger.RespFormat, action, *resp)
-		results, err = bp.ObtainSdkValue("Result.NetworkInterfaceSets", *resp)
+		logger.Debug(logger.RespFormat, action, condition, *resp)
+
+		results, err := bp.ObtainSdkValue("Result.NetworkInterfaceSets", *resp)
 		if err != nil {
-			return data, err
+			return nil, 0, err
 		}
 		if results == nil {
 			results = []interface{}{}
 		}
-		if data, ok = results.([]interface{}); !ok {
-			return data, errors.New("Result.NetworkInterfaceSets is not Slice")
+		data, ok := results.([]interface{})
+		if !ok {
+			return nil, 0, errors.New("Result.NetworkInterfaceSets is not Slice")
 		}
-		return data, err
+		totalCount, err := bp.ObtainSdkValue("Result.TotalCount", *resp)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, _ := totalCount.(float64)
+		return data, int(total), nil
 	})
 }
 
@@ -338,10 +333,6 @@ func (s *VestackNetworkInterfaceService) ModifyResource(resourceData *schema.Res
 		}
 	}
 
-	// 更新Tags
-	setResourceTagsCallbacks := bp.SetResourceTags(s.Client, "TagResources", "UntagResources", "eni", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -452,3 +443,12 @@ func (s *VestackNetworkInterfaceService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackNetworkInterfaceService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "eni",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
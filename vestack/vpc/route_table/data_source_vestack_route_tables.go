@@ -112,6 +112,7 @@ func DataSourceVestackRouteTables() *schema.Resource {
 							Computed:    true,
 							Description: "The ProjectName of the route table.",
 						},
+						"tags": bp.TagsSchemaComputed(),
 					},
 				},
 			},
@@ -55,6 +55,7 @@ func ResourceVestackRouteTable() *schema.Resource {
 				Optional:    true,
 				Description: "The ProjectName of the route table.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 }
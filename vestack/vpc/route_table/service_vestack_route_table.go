@@ -150,6 +150,11 @@ func (s *VestackRouteTableService) CreateResource(resourceData *schema.ResourceD
 		Call: bp.SdkCall{
 			Action:      "CreateRouteTable",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			ExecuteCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (*map[string]interface{}, error) {
 				logger.Debug(logger.ReqFormat, call.Action, call.SdkParam)
 				return s.Client.VpcClient.CreateRouteTableCommon(call.SdkParam)
@@ -183,6 +188,11 @@ func (s *VestackRouteTableService) ModifyResource(resourceData *schema.ResourceD
 		Call: bp.SdkCall{
 			Action:      "ModifyRouteTableAttributes",
 			ConvertMode: bp.RequestConvertAll,
+			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
+			},
 			BeforeCall: func(d *schema.ResourceData, client *bp.SdkClient, call bp.SdkCall) (bool, error) {
 				(*call.SdkParam)["RouteTableId"] = d.Id()
 				return true, nil
@@ -257,6 +267,7 @@ func (s *VestackRouteTableService) DatasourceResources(*schema.ResourceData, *sc
 			"ids": {
 				TargetField: "RouteTableIds",
 			},
+			"tags": bp.TagFilterConvert(),
 		},
 		NameField:    "RouteTableName",
 		IdField:      "RouteTableId",
@@ -274,6 +285,16 @@ func (s *VestackRouteTableService) ReadResourceId(id string) string {
 	return id
 }
 
+func getUniversalInfo(actionName string) bp.UniversalInfo {
+	return bp.UniversalInfo{
+		ServiceName: "vpc",
+		Version:     "2020-04-01",
+		HttpMethod:  bp.GET,
+		ContentType: bp.Default,
+		Action:      actionName,
+	}
+}
+
 func (s *VestackRouteTableService) ProjectTrn() *bp.ProjectTrn {
 	return &bp.ProjectTrn{
 		ServiceName:          "vpc",
@@ -282,3 +303,12 @@ func (s *VestackRouteTableService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackRouteTableService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "routetable",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
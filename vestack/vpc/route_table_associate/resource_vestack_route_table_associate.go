@@ -20,12 +20,10 @@ $ terraform import vestack_route_table_associate.default vtb-2fdzao4h726f45*****
 
 func ResourceVestackRouteTableAssociate() *schema.Resource {
 	return &schema.Resource{
-		Delete: resourceVestackRouteTableAssociateDelete,
-		Create: resourceVestackRouteTableAssociateCreate,
-		Read:   resourceVestackRouteTableAssociateRead,
-		Importer: &schema.ResourceImporter{
-			State: routeTableAssociateImporter,
-		},
+		Delete:   resourceVestackRouteTableAssociateDelete,
+		Create:   resourceVestackRouteTableAssociateCreate,
+		Read:     resourceVestackRouteTableAssociateRead,
+		Importer: routeTableAssociateCompositeId.Importer(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(30 * time.Minute),
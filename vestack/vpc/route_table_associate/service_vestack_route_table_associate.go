@@ -80,9 +80,9 @@ func (s *VestackRouteTableAssociateService) ReadResource(resourceData *schema.Re
 		associateId = s.ReadResourceId(resourceData.Id())
 	}
 
-	ids = strings.Split(associateId, ":")
-	if len(ids) != 2 {
-		return map[string]interface{}{}, fmt.Errorf("invalid route table associate id: %v", associateId)
+	ids, err = routeTableAssociateCompositeId.Decode(associateId)
+	if err != nil {
+		return map[string]interface{}{}, err
 	}
 	routeTableId = ids[0]
 	targetSubnetId = ids[1]
@@ -181,7 +181,11 @@ func (s *VestackRouteTableAssociateService) CreateResource(resourceData *schema.
 				return s.Client.VpcClient.AssociateRouteTableCommon(call.SdkParam)
 			},
 			AfterCall: func(d *schema.ResourceData, client *bp.SdkClient, resp *map[string]interface{}, call bp.SdkCall) error {
-				d.SetId(fmt.Sprint((*call.SdkParam)["RouteTableId"], ":", (*call.SdkParam)["SubnetId"]))
+				id, err := routeTableAssociateCompositeId.ResourceId(d)
+				if err != nil {
+					return err
+				}
+				d.SetId(id)
 				return nil
 			},
 			Refresh: &bp.StateRefresh{
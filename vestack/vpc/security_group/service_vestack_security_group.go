@@ -27,41 +27,33 @@ func (s *VestackSecurityGroupService) GetClient() *bp.SdkClient {
 }
 
 func (s *VestackSecurityGroupService) ReadResources(m map[string]interface{}) (data []interface{}, err error) {
-	var (
-		resp    *map[string]interface{}
-		results interface{}
-		ok      bool
-	)
-	return bp.WithPageNumberQuery(m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, error) {
+	return bp.WithParallelPageNumberQuery(s, m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, int, error) {
 		vpcClient := s.Client.VpcClient
 		action := "DescribeSecurityGroups"
 		logger.Debug(logger.ReqFormat, action, condition)
-		if condition == nil {
-			resp, err = vpcClient.DescribeSecurityGroupsCommon(nil)
-			if err != nil {
-				return data, err
-			}
-		} else {
-			resp, err = vpcClient.DescribeSecurityGroupsCommon(&condition)
-			if err != nil {
-				return data, err
-			}
+		resp, err := vpcClient.DescribeSecurityGroupsCommon(&condition)
+		if err != nil {
+			return nil, 0, err
 		}
+		logger.Debug(logger.RespFormat, action, condition, *resp)
 
-		results, err = bp.ObtainSdkValue("Result.SecurityGroups", *resp)
-
+		results, err := bp.ObtainSdkValue("Result.SecurityGroups", *resp)
 		if err != nil {
-			return data, err
+			return nil, 0, err
 		}
 		if results == nil {
 			results = []interface{}{}
 		}
-		if data, ok = results.([]interface{}); !ok {
-			return data, errors.New("Result.SecurityGroups is not Slice")
+		data, ok := results.([]interface{})
+		if !ok {
+			return nil, 0, errors.New("Result.SecurityGroups is not Slice")
 		}
-		logger.Debug(logger.ReqFormat, "", data)
-
-		return data, err
+		totalCount, err := bp.ObtainSdkValue("Result.TotalCount", *resp)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, _ := totalCount.(float64)
+		return data, int(total), nil
 	})
 }
 
@@ -208,10 +200,6 @@ func (s *VestackSecurityGroupService) ModifyResource(resourceData *schema.Resour
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := bp.SetResourceTags(s.Client, "TagResources", "UntagResources", "securitygroup", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -308,3 +296,12 @@ func (s *VestackSecurityGroupService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackSecurityGroupService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "securitygroup",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
@@ -22,7 +22,7 @@ $ terraform import vestack_security_group_rule.default ID is a string concatenat
 */
 
 func ResourceVestackSecurityGroupRule() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Create: resourceVestackSecurityGroupRuleCreate,
 		Read:   resourceVestackSecurityGroupRuleRead,
 		Update: resourceVestackSecurityGroupRuleUpdate,
@@ -70,14 +70,14 @@ func ResourceVestackSecurityGroupRule() *schema.Resource {
 				Required:     true,
 				ForceNew:     true,
 				ValidateFunc: validation.IntBetween(-1, 65535),
-				Description:  "Port start of egress/ingress Rule.",
+				Description:  "Port start of egress/ingress Rule. It must be in 1~65535 when protocol is `tcp` or `udp`, and be -1 when protocol is `icmp`, `icmpv6` or `all`.",
 			},
 			"port_end": {
 				Type:         schema.TypeInt,
 				Required:     true,
 				ForceNew:     true,
 				ValidateFunc: validation.IntBetween(-1, 65535),
-				Description:  "Port end of egress/ingress Rule.",
+				Description:  "Port end of egress/ingress Rule. It must be in 1~65535 when protocol is `tcp` or `udp`, and be -1 when protocol is `icmp`, `icmpv6` or `all`. It must not be less than `port_start`.",
 			},
 			"cidr_ip": {
 				Type:          schema.TypeString,
@@ -124,6 +124,7 @@ func ResourceVestackSecurityGroupRule() *schema.Resource {
 			},
 		},
 	}
+	return bp.ResourceDiffConstraints(resource, &VestackSecurityGroupRuleService{})
 }
 
 func importSecurityGroupRule(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
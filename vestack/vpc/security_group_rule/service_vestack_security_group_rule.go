@@ -342,3 +342,11 @@ func validateProtocol(protocol string, start, end int) error {
 	}
 	return nil
 }
+
+func (s *VestackSecurityGroupRuleService) DiffConstraints() []bp.DiffConstraint {
+	return []bp.DiffConstraint{
+		bp.When("protocol", "tcp", "udp").IntBetween(1, 65535, "port_start", "port_end"),
+		bp.When("protocol", "icmp", "icmpv6", "all").IntBetween(-1, -1, "port_start", "port_end"),
+		bp.IntNotGreater("port_start", "port_end"),
+	}
+}
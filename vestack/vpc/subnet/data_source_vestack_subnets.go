@@ -161,6 +161,7 @@ func DataSourceVestackSubnets() *schema.Resource {
 								},
 							},
 						},
+						"tags": bp.TagsSchemaComputed(),
 					},
 				},
 			},
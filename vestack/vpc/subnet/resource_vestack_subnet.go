@@ -100,6 +100,7 @@ func ResourceVestackSubnet() *schema.Resource {
 				Computed:    true,
 				Description: "Creation time of Subnet.",
 			},
+			"tags": bp.TagsSchema(),
 		},
 	}
 }
@@ -30,40 +30,33 @@ func (s *VestackSubnetService) GetClient() *bp.SdkClient {
 }
 
 func (s *VestackSubnetService) ReadResources(m map[string]interface{}) (data []interface{}, err error) {
-	var (
-		resp    *map[string]interface{}
-		results interface{}
-		ok      bool
-	)
-	return bp.WithPageNumberQuery(m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, error) {
+	return bp.WithParallelPageNumberQuery(s, m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, int, error) {
 		vpcClient := s.Client.VpcClient
 		action := "DescribeSubnets"
 		logger.Debug(logger.ReqFormat, action, condition)
-		if condition == nil {
-			resp, err = vpcClient.DescribeSubnetsCommon(nil)
-			if err != nil {
-				return data, err
-			}
-		} else {
-			resp, err = vpcClient.DescribeSubnetsCommon(&condition)
-			if err != nil {
-				return data, err
-			}
+		resp, err := vpcClient.DescribeSubnetsCommon(&condition)
+		if err != nil {
+			return nil, 0, err
 		}
-
 		logger.Debug(logger.RespFormat, action, condition, *resp)
 
-		results, err = bp.ObtainSdkValue("Result.Subnets", *resp)
+		results, err := bp.ObtainSdkValue("Result.Subnets", *resp)
 		if err != nil {
-			return data, err
+			return nil, 0, err
 		}
 		if results == nil {
 			results = []interface{}{}
 		}
-		if data, ok = results.([]interface{}); !ok {
-			return data, errors.New("Result.Subnets is not Slice")
+		data, ok := results.([]interface{})
+		if !ok {
+			return nil, 0, errors.New("Result.Subnets is not Slice")
 		}
-		return data, err
+		totalCount, err := bp.ObtainSdkValue("Result.TotalCount", *resp)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, _ := totalCount.(float64)
+		return data, int(total), nil
 	})
 }
 
@@ -168,6 +161,9 @@ func (s *VestackSubnetService) CreateResource(resourceData *schema.ResourceData,
 			},
 			ConvertMode: bp.RequestConvertAll,
 			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
 				"ipv6_cidr_block": {
 					Ignore: true,
 				},
@@ -213,6 +209,9 @@ func (s *VestackSubnetService) ModifyResource(resourceData *schema.ResourceData,
 			Action:      "ModifySubnetAttributes",
 			ConvertMode: bp.RequestConvertAll,
 			Convert: map[string]bp.RequestConvert{
+				"tags": {
+					Ignore: true,
+				},
 				"ipv6_cidr_block": {
 					Ignore: true,
 				},
@@ -294,6 +293,7 @@ func (s *VestackSubnetService) DatasourceResources(*schema.ResourceData, *schema
 				TargetField: "SubnetIds",
 				ConvertType: bp.ConvertWithN,
 			},
+			"tags": bp.TagFilterConvert(),
 		},
 		NameField:    "SubnetName",
 		IdField:      "SubnetId",
@@ -315,3 +315,22 @@ func (s *VestackSubnetService) DatasourceResources(*schema.ResourceData, *schema
 func (s *VestackSubnetService) ReadResourceId(id string) string {
 	return id
 }
+
+func getUniversalInfo(actionName string) bp.UniversalInfo {
+	return bp.UniversalInfo{
+		ServiceName: "vpc",
+		Version:     "2020-04-01",
+		HttpMethod:  bp.GET,
+		ContentType: bp.Default,
+		Action:      actionName,
+	}
+}
+
+func (s *VestackSubnetService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "subnet",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: false,
+	}
+}
@@ -204,10 +204,6 @@ func (s *VestackTrafficMirrorFilterService) ModifyResource(resourceData *schema.
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "trafficmirrorfilter", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -306,3 +302,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackTrafficMirrorFilterService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "trafficmirrorfilter",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
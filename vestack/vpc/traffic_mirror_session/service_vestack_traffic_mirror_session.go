@@ -234,10 +234,6 @@ func (s *VestackTrafficMirrorSessionService) ModifyResource(resourceData *schema
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "trafficmirrorsession", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -339,3 +335,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackTrafficMirrorSessionService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "trafficmirrorsession",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
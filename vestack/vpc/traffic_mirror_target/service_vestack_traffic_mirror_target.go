@@ -204,10 +204,6 @@ func (s *VestackTrafficMirrorTargetService) ModifyResource(resourceData *schema.
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := ve.SetResourceTags(s.Client, "TagResources", "UntagResources", "trafficmirrortarget", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -302,3 +298,12 @@ func getUniversalInfo(actionName string) ve.UniversalInfo {
 		Action:      actionName,
 	}
 }
+
+func (s *VestackTrafficMirrorTargetService) TagInfo() *ve.TagInfo {
+	return &ve.TagInfo{
+		ResourceType:   "trafficmirrortarget",
+		Flavor:         ve.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}
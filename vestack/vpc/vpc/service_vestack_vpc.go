@@ -26,39 +26,33 @@ func (s *VestackVpcService) GetClient() *bp.SdkClient {
 }
 
 func (s *VestackVpcService) ReadResources(m map[string]interface{}) (data []interface{}, err error) {
-	var (
-		resp    *map[string]interface{}
-		results interface{}
-		ok      bool
-	)
-	return bp.WithPageNumberQuery(m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, error) {
+	return bp.WithParallelPageNumberQuery(s, m, "PageSize", "PageNumber", 20, 1, func(condition map[string]interface{}) ([]interface{}, int, error) {
 		vpcClient := s.Client.VpcClient
 		action := "DescribeVpcs"
 		logger.Debug(logger.ReqFormat, action, condition)
-		if condition == nil {
-			resp, err = vpcClient.DescribeVpcsCommon(nil)
-			if err != nil {
-				return data, err
-			}
-		} else {
-			resp, err = vpcClient.DescribeVpcsCommon(&condition)
-			if err != nil {
-				return data, err
-			}
+		resp, err := vpcClient.DescribeVpcsCommon(&condition)
+		if err != nil {
+			return nil, 0, err
 		}
-		logger.Debug(logger.RespFormat, action, resp)
-		results, err = bp.ObtainSdkValue("Result.Vpcs", *resp)
+		logger.Debug(logger.RespFormat, action, condition, *resp)
+
+		results, err := bp.ObtainSdkValue("Result.Vpcs", *resp)
 		if err != nil {
-			return data, err
+			return nil, 0, err
 		}
 		if results == nil {
 			results = []interface{}{}
 		}
-		if data, ok = results.([]interface{}); !ok {
-			return data, errors.New("Result.Vpcs is not Slice")
+		data, ok := results.([]interface{})
+		if !ok {
+			return nil, 0, errors.New("Result.Vpcs is not Slice")
 		}
-
-		return data, err
+		totalCount, err := bp.ObtainSdkValue("Result.TotalCount", *resp)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, _ := totalCount.(float64)
+		return data, int(total), nil
 	})
 }
 
@@ -208,10 +202,6 @@ func (s *VestackVpcService) ModifyResource(resourceData *schema.ResourceData, re
 	}
 	callbacks = append(callbacks, callback)
 
-	// 更新Tags
-	setResourceTagsCallbacks := bp.SetResourceTags(s.Client, "TagResources", "UntagResources", "vpc", resourceData, getUniversalInfo)
-	callbacks = append(callbacks, setResourceTagsCallbacks...)
-
 	return callbacks
 }
 
@@ -302,3 +292,12 @@ func (s *VestackVpcService) ProjectTrn() *bp.ProjectTrn {
 		ProjectSchemaField:   "project_name",
 	}
 }
+
+func (s *VestackVpcService) TagInfo() *bp.TagInfo {
+	return &bp.TagInfo{
+		ResourceType:   "vpc",
+		Flavor:         bp.TagFlavorDefault,
+		UniversalInfo:  getUniversalInfo,
+		CreateWithTags: true,
+	}
+}